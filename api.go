@@ -0,0 +1,173 @@
+package dvls
+
+import (
+	"context"
+	"iter"
+)
+
+// VaultsAPI is the interface implemented by Vaults, so that code using it can be tested with a fake.
+type VaultsAPI interface {
+	List() ([]Vault, error)
+	ListWithContext(ctx context.Context) ([]Vault, error)
+	All(ctx context.Context) iter.Seq2[Vault, error]
+	ListPage(ctx context.Context, opts PageOptions) (Page[Vault], error)
+	Get(vaultId string) (Vault, error)
+	GetWithContext(ctx context.Context, vaultId string) (Vault, error)
+	GetByName(name string) (Vault, error)
+	GetByNameWithContext(ctx context.Context, name string) (Vault, error)
+	New(vault Vault) (Vault, error)
+	NewWithContext(ctx context.Context, vault Vault) (Vault, error)
+	Update(vault Vault) (Vault, error)
+	UpdateWithContext(ctx context.Context, vault Vault) (Vault, error)
+	Delete(vaultId string) error
+	DeleteWithContext(ctx context.Context, vaultId string) error
+}
+
+// CredentialEntriesAPI is the interface implemented by EntryCredentialService.
+type CredentialEntriesAPI interface {
+	Get(entry Entry) (Entry, error)
+	GetWithContext(ctx context.Context, entry Entry) (Entry, error)
+	GetById(vaultId string, entryId string) (Entry, error)
+	GetByIdWithContext(ctx context.Context, vaultId string, entryId string) (Entry, error)
+	GetByName(vaultId, name, subType string, opts GetByNameOptions) (Entry, error)
+	GetByNameWithContext(ctx context.Context, vaultId, name, subType string, opts GetByNameOptions) (Entry, error)
+	GetEntries(vaultId string, opts GetEntriesOptions) ([]Entry, error)
+	GetEntriesWithContext(ctx context.Context, vaultId string, opts GetEntriesOptions) ([]Entry, error)
+	All(ctx context.Context, vaultId string, opts GetEntriesOptions) iter.Seq2[Entry, error]
+	ListPage(ctx context.Context, vaultId string, opts GetEntriesOptions, page PageOptions) (Page[Entry], error)
+	New(entry Entry) (string, error)
+	NewWithContext(ctx context.Context, entry Entry) (string, error)
+	Update(entry Entry) (Entry, error)
+	UpdateWithContext(ctx context.Context, entry Entry) (Entry, error)
+	Delete(e Entry) error
+	DeleteWithContext(ctx context.Context, e Entry) error
+	DeleteById(vaultId string, entryId string) error
+	DeleteByIdWithContext(ctx context.Context, vaultId string, entryId string) error
+}
+
+// FolderEntriesAPI is the interface implemented by EntryFolderService.
+type FolderEntriesAPI interface {
+	Get(entry Entry) (Entry, error)
+	GetWithContext(ctx context.Context, entry Entry) (Entry, error)
+	GetById(vaultId string, entryId string) (Entry, error)
+	GetByIdWithContext(ctx context.Context, vaultId string, entryId string) (Entry, error)
+	GetByName(vaultId, name string, opts GetByNameOptions) (Entry, error)
+	GetByNameWithContext(ctx context.Context, vaultId, name string, opts GetByNameOptions) (Entry, error)
+	GetEntries(vaultId string, opts GetEntriesOptions) ([]Entry, error)
+	GetEntriesWithContext(ctx context.Context, vaultId string, opts GetEntriesOptions) ([]Entry, error)
+	All(ctx context.Context, vaultId string, opts GetEntriesOptions) iter.Seq2[Entry, error]
+	ListPage(ctx context.Context, vaultId string, opts GetEntriesOptions, page PageOptions) (Page[Entry], error)
+	New(entry Entry) (string, error)
+	NewWithContext(ctx context.Context, entry Entry) (string, error)
+	Update(entry Entry) (Entry, error)
+	UpdateWithContext(ctx context.Context, entry Entry) (Entry, error)
+	Delete(e Entry) error
+	DeleteWithContext(ctx context.Context, e Entry) error
+	DeleteById(vaultId string, entryId string) error
+	DeleteByIdWithContext(ctx context.Context, vaultId string, entryId string) error
+}
+
+// HostEntriesAPI is the interface implemented by EntryHostService.
+type HostEntriesAPI interface {
+	Get(entryId string) (EntryHost, error)
+	GetWithContext(ctx context.Context, entryId string) (EntryHost, error)
+	GetHostDetails(entry EntryHost) (EntryHost, error)
+	GetHostDetailsWithContext(ctx context.Context, entry EntryHost) (EntryHost, error)
+}
+
+// WebsiteEntriesAPI is the interface implemented by EntryWebsiteService.
+type WebsiteEntriesAPI interface {
+	Get(entryId string) (EntryWebsite, error)
+	GetWithContext(ctx context.Context, entryId string) (EntryWebsite, error)
+	GetWebsiteDetails(entry EntryWebsite) (EntryWebsite, error)
+	GetWebsiteDetailsWithContext(ctx context.Context, entry EntryWebsite) (EntryWebsite, error)
+}
+
+// CertificateEntriesAPI is the interface implemented by EntryCertificateService.
+type CertificateEntriesAPI interface {
+	Get(entryId string) (EntryCertificate, error)
+	GetWithContext(ctx context.Context, entryId string) (EntryCertificate, error)
+	GetFileContent(entryId string) ([]byte, error)
+	GetFileContentWithContext(ctx context.Context, entryId string) ([]byte, error)
+	GetPassword(entry EntryCertificate) (EntryCertificate, error)
+	GetPasswordWithContext(ctx context.Context, entry EntryCertificate) (EntryCertificate, error)
+	NewURL(entry EntryCertificate) (EntryCertificate, error)
+	NewURLWithContext(ctx context.Context, entry EntryCertificate) (EntryCertificate, error)
+	NewFile(entry EntryCertificate, content []byte) (EntryCertificate, error)
+	NewFileWithContext(ctx context.Context, entry EntryCertificate, content []byte) (EntryCertificate, error)
+	Update(entry EntryCertificate) (EntryCertificate, error)
+	UpdateWithContext(ctx context.Context, entry EntryCertificate) (EntryCertificate, error)
+	Delete(entryId string) error
+	DeleteWithContext(ctx context.Context, entryId string) error
+}
+
+// ServerAPI groups the methods of Client reading the server information.
+type ServerAPI interface {
+	GetPublicServerInfo() (Server, error)
+	GetPublicServerInfoWithContext(ctx context.Context) (Server, error)
+	GetPrivateServerInfo() (Server, error)
+	GetPrivateServerInfoWithContext(ctx context.Context) (Server, error)
+	GetServerTimezones() ([]Timezone, error)
+	GetServerTimezonesWithContext(ctx context.Context) ([]Timezone, error)
+	GetServerZone() (*ServerZone, error)
+	GetServerZoneWithContext(ctx context.Context) (*ServerZone, error)
+	Probe(ctx context.Context) (ProbeResult, error)
+	Ping(ctx context.Context) error
+}
+
+// ClientAPI is the interface implemented by Client, so that consumer code can depend on an abstraction
+// and be tested with fakes. Since interfaces cannot expose fields, the services are returned by methods
+// named after them, such as VaultService for Client.Vaults.
+type ClientAPI interface {
+	ServerAPI
+
+	VaultService() VaultsAPI
+	CredentialEntryService() CredentialEntriesAPI
+	FolderEntryService() FolderEntriesAPI
+	HostEntryService() HostEntriesAPI
+	WebsiteEntryService() WebsiteEntriesAPI
+	CertificateEntryService() CertificateEntriesAPI
+
+	Logout(ctx context.Context) error
+	Close() error
+}
+
+var (
+	_ VaultsAPI             = (*Vaults)(nil)
+	_ CredentialEntriesAPI  = (*EntryCredentialService)(nil)
+	_ FolderEntriesAPI      = (*EntryFolderService)(nil)
+	_ HostEntriesAPI        = (*EntryHostService)(nil)
+	_ WebsiteEntriesAPI     = (*EntryWebsiteService)(nil)
+	_ CertificateEntriesAPI = (*EntryCertificateService)(nil)
+	_ ClientAPI             = (*Client)(nil)
+)
+
+// VaultService returns c.Vaults.
+func (c *Client) VaultService() VaultsAPI {
+	return c.Vaults
+}
+
+// CredentialEntryService returns c.Entries.Credential.
+func (c *Client) CredentialEntryService() CredentialEntriesAPI {
+	return c.Entries.Credential
+}
+
+// FolderEntryService returns c.Entries.Folder.
+func (c *Client) FolderEntryService() FolderEntriesAPI {
+	return c.Entries.Folder
+}
+
+// HostEntryService returns c.Entries.Host.
+func (c *Client) HostEntryService() HostEntriesAPI {
+	return c.Entries.Host
+}
+
+// WebsiteEntryService returns c.Entries.Website.
+func (c *Client) WebsiteEntryService() WebsiteEntriesAPI {
+	return c.Entries.Website
+}
+
+// CertificateEntryService returns c.Entries.Certificate.
+func (c *Client) CertificateEntryService() CertificateEntriesAPI {
+	return c.Entries.Certificate
+}
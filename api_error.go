@@ -0,0 +1,80 @@
+package dvls
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// APIError is the structured error body returned by the DVLS v1 API along with a non-2xx status code.
+// It is available from a RequestError with errors.As.
+type APIError struct {
+	StatusCode int
+	// Code is the error code reported by the server, if any.
+	Code    string
+	Message string
+	// Details holds validation errors keyed by the name of the offending field. Like the rest of the error,
+	// it is decoded from the redacted body, so the errors of secret fields, such as Password, are redacted.
+	Details map[string][]string
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unexpected status code %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&sb, " (%s)", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&sb, ": %s", e.Message)
+	}
+
+	return sb.String()
+}
+
+// parseAPIError decodes a v1 API error body. It returns nil when the body is not a JSON error object.
+func parseAPIError(statusCode int, body []byte) *APIError {
+	raw := struct {
+		Code      json.RawMessage     `json:"code"`
+		ErrorCode json.RawMessage     `json:"errorCode"`
+		Message   string              `json:"message"`
+		Title     string              `json:"title"`
+		Detail    string              `json:"detail"`
+		Errors    map[string][]string `json:"errors"`
+	}{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Code:       rawErrorCode(raw.ErrorCode),
+		Message:    raw.Message,
+		Details:    raw.Errors,
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = rawErrorCode(raw.Code)
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(raw.Title + " " + raw.Detail)
+	}
+
+	if apiErr.Code == "" && apiErr.Message == "" && len(apiErr.Details) == 0 {
+		return nil
+	}
+
+	return apiErr
+}
+
+// rawErrorCode returns an error code sent either as a JSON string or as a number.
+func rawErrorCode(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var code string
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return code
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,64 @@
+package dvls
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVaults overrides GetByName and panics on the other methods of VaultsAPI.
+type fakeVaults struct {
+	VaultsAPI
+	vaults map[string]Vault
+}
+
+func (f fakeVaults) GetByName(name string) (Vault, error) {
+	vault, ok := f.vaults[name]
+	if !ok {
+		return Vault{}, ErrVaultNotFound
+	}
+
+	return vault, nil
+}
+
+func vaultIdByName(client ClientAPI, name string) (string, error) {
+	vault, err := client.VaultService().GetByName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return vault.Id, nil
+}
+
+type fakeClient struct {
+	ClientAPI
+	vaults VaultsAPI
+}
+
+func (f fakeClient) VaultService() VaultsAPI {
+	return f.vaults
+}
+
+func TestClientAPI_Fake(t *testing.T) {
+	client := fakeClient{vaults: fakeVaults{vaults: map[string]Vault{"Alpha": {Id: "vault-1", Name: "Alpha"}}}}
+
+	id, err := vaultIdByName(client, "Alpha")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-1", id)
+
+	_, err = vaultIdByName(client, "Beta")
+	assert.ErrorIs(t, err, ErrVaultNotFound)
+}
+
+func TestClientAPI_Services(t *testing.T) {
+	client := newTestClient(t, http.NewServeMux())
+
+	assert.Same(t, client.Vaults, client.VaultService())
+	assert.Same(t, client.Entries.Credential, client.CredentialEntryService())
+	assert.Same(t, client.Entries.Folder, client.FolderEntryService())
+	assert.Same(t, client.Entries.Host, client.HostEntryService())
+	assert.Same(t, client.Entries.Website, client.WebsiteEntryService())
+	assert.Same(t, client.Entries.Certificate, client.CertificateEntryService())
+}
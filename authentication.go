@@ -1,20 +1,37 @@
 package dvls
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"strings"
+	"time"
 )
 
 // Client represents the DVLS client used to communicate with the API.
+// A Client is safe for concurrent use by multiple goroutines and must not be copied.
 type Client struct {
-	client     *http.Client
-	baseUri    string
-	credential credentials
+	client  *http.Client
+	baseUri string
+	// authenticator is guarded by tokens.mu so that Logout can discard the credentials.
+	authenticator Authenticator
+	tokens        tokenManager
+	userAgent     string
+
+	retryPolicy     *RetryPolicy
+	limiter         *requestLimiter
+	pageConcurrency int
+	maxResponseSize int64
+
+	// doer is the middleware chain around client, or nil when no middleware is configured.
+	doer        Doer
+	hooks       []Hooks
+	hookSecrets bool
+	logger      *slog.Logger
+	metrics     MetricsRecorder
 
 	common service
 
@@ -26,39 +43,99 @@ type service struct {
 	client *Client
 }
 
-type credentials struct {
-	appKey    string
-	appSecret string
-	token     string
-}
-
-type loginResponse struct {
-	TokenId string
-}
-
 const (
-	loginEndpoint    string = "/api/v1/login"
 	isLoggedEndpoint string = "/api/is-logged"
+	logoutEndpoint   string = "/api/v1/logout"
 )
 
-const loginContentType = "application/x-www-form-urlencoded"
+// ErrClientClosed is returned by requests sent with a Client after Logout or Close was called.
+var ErrClientClosed = errors.New("dvls client is closed")
 
 // NewClient returns a new Client configured with the specified credentials and
 // base URI. baseUri should be the full URI to your DVLS instance (ex.: https://dvls.your-dvls-instance.com)
-func NewClient(appKey string, appSecret string, baseUri string) (Client, error) {
-	credential := credentials{appKey: appKey, appSecret: appSecret}
-	client := Client{
-		client:     &http.Client{},
-		baseUri:    baseUri,
-		credential: credential,
+// The client can be customized with ClientOption values such as WithTimeout or WithRootCAs.
+// For context support, use NewClientWithContext instead.
+func NewClient(appKey string, appSecret string, baseUri string, opts ...ClientOption) (*Client, error) {
+	return NewClientWithContext(context.Background(), appKey, appSecret, baseUri, opts...)
+}
+
+// NewClientWithContext returns a new Client configured with the specified credentials and
+// base URI. baseUri should be the full URI to your DVLS instance (ex.: https://dvls.your-dvls-instance.com)
+// The provided context can be used to cancel the initial login.
+func NewClientWithContext(ctx context.Context, appKey string, appSecret string, baseUri string, opts ...ClientOption) (*Client, error) {
+	return NewClientWithAuthenticator(ctx, baseUri, AppKeyAuthenticator{AppKey: appKey, AppSecret: appSecret}, opts...)
+}
+
+// NewClientWithAuthenticator returns a new Client that obtains its session tokens from authenticator.
+// baseUri should be the full URI to your DVLS instance (ex.: https://dvls.your-dvls-instance.com)
+// The provided context can be used to cancel the initial login.
+func NewClientWithAuthenticator(ctx context.Context, baseUri string, authenticator Authenticator, opts ...ClientOption) (*Client, error) {
+	if authenticator == nil {
+		return nil, fmt.Errorf("authenticator is required")
+	}
+
+	var cfg clientConfig
+	for _, opt := range opts {
+		opt(&cfg)
 	}
 
-	err := client.login()
+	httpClient, err := cfg.buildHTTPClient()
 	if err != nil {
-		return Client{}, fmt.Errorf("login failed \"%w\"", err)
+		return nil, fmt.Errorf("invalid client options: %w", err)
 	}
 
-	client.common.client = &client
+	limiter, err := cfg.buildRequestLimiter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid client options: %w", err)
+	}
+
+	pageConcurrency := defaultPageConcurrency
+	if cfg.pageConcurrency != nil {
+		if *cfg.pageConcurrency < 1 {
+			return nil, fmt.Errorf("invalid client options: page concurrency must be at least 1")
+		}
+		pageConcurrency = *cfg.pageConcurrency
+	}
+
+	if cfg.maxResponseSize < 0 {
+		return nil, fmt.Errorf("invalid client options: maximum response size must not be negative")
+	}
+
+	client := &Client{
+		client:        httpClient,
+		baseUri:       baseUri,
+		authenticator: authenticator,
+		userAgent:     cfg.userAgent,
+
+		retryPolicy:     cfg.retryPolicy,
+		limiter:         limiter,
+		pageConcurrency: pageConcurrency,
+		maxResponseSize: cfg.maxResponseSize,
+
+		doer:        cfg.buildDoer(httpClient),
+		hooks:       cfg.hooks,
+		hookSecrets: cfg.hookSecrets,
+		logger:      cfg.logger,
+		metrics:     cfg.metrics,
+	}
+	if cfg.tokenMaxAge != nil {
+		client.tokens.maxAge = *cfg.tokenMaxAge
+	}
+
+	if cfg.checkServerVersion {
+		if err := client.checkServerVersion(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.lazyLogin {
+		err = client.loginWithContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("login failed \"%w\"", err)
+		}
+	}
+
+	client.common.client = client
 
 	client.Entries = &Entries{
 		Certificate: (*EntryCertificateService)(&client.common),
@@ -77,32 +154,70 @@ func (c *Client) login() error {
 }
 
 func (c *Client) loginWithContext(ctx context.Context) error {
-	form := url.Values{}
-	form.Set("AppKey", c.credential.appKey)
-	form.Set("AppSecret", c.credential.appSecret)
-	loginBody := form.Encode()
+	c.tokens.mu.Lock()
+	authenticator := c.authenticator
+	c.tokens.mu.Unlock()
 
-	reqUrl, err := url.JoinPath(c.baseUri, loginEndpoint)
-	if err != nil {
-		return fmt.Errorf("failed to build login url: %w", err)
+	if authenticator == nil {
+		return fmt.Errorf("no authenticator configured")
 	}
 
-	resp, err := c.rawRequestWithContext(ctx, reqUrl, http.MethodPost, loginContentType, bytes.NewBufferString(loginBody))
+	relogin := c.tokens.current() != ""
+	start := time.Now()
+	token, err := authenticator.Authenticate(ctx, c)
+	if err == nil && token == "" {
+		err = ErrEmptyToken
+	}
+	c.recordLogin(relogin, time.Since(start), err)
 	if err != nil {
-		return fmt.Errorf("error while submitting login request: %w", err)
+		return err
+	}
+
+	c.tokens.set(token, time.Now())
+
+	return nil
+}
+
+// Logout invalidates the session token on the server and discards the token and credentials held by
+// the client. Requests sent with the client afterwards fail with ErrClientClosed.
+// The client is closed even if the server could not be reached, in which case the error is returned.
+// The provided context can be used to cancel the request.
+func (c *Client) Logout(ctx context.Context) error {
+	c.tokens.mu.Lock()
+	if c.tokens.closed {
+		c.tokens.mu.Unlock()
+		return ErrClientClosed
+	}
+
+	token := c.tokens.token
+	c.tokens.closed = true
+	c.tokens.token = ""
+	c.tokens.issuedAt = time.Time{}
+	c.authenticator = nil
+	c.tokens.mu.Unlock()
+
+	if token == "" {
+		return nil
 	}
 
-	var loginResponse loginResponse
-	err = json.Unmarshal(resp.Response, &loginResponse)
+	reqUrl, err := url.JoinPath(c.baseUri, logoutEndpoint)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal response body: %w", err)
+		return fmt.Errorf("failed to build logout url: %w", err)
 	}
 
-	c.credential.token = loginResponse.TokenId
+	_, err = c.sendAttemptWithContext(ctx, token, reqUrl, http.MethodPost, defaultContentType, nil, RequestOptions{RawBody: true})
+	if err != nil && !isAuthenticationExpired(Response{}, err) {
+		return fmt.Errorf("error while submitting logout request: %w", err)
+	}
 
 	return nil
 }
 
+// Close logs out of DVLS, see Logout. It implements the io.Closer interface.
+func (c *Client) Close() error {
+	return c.Logout(context.Background())
+}
+
 func (c *Client) isLogged() (bool, error) {
 	return c.isLoggedWithContext(context.Background())
 }
@@ -113,14 +228,16 @@ func (c *Client) isLoggedWithContext(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("failed to build isLogged url: %w", err)
 	}
 
-	resp, err := c.rawRequestWithContext(ctx, reqUrl, http.MethodGet, defaultContentType, nil)
-	if err != nil && !strings.Contains(err.Error(), "json: cannot unmarshal bool into Go value") {
+	resp, err := c.rawRequestWithContext(ctx, reqUrl, http.MethodGet, defaultContentType, nil, RequestOptions{RawBody: true})
+	if err != nil {
 		return false, fmt.Errorf("error while submitting isLogged request: %w", err)
 	}
 
-	if string(resp.Response) == "false" {
-		return false, nil
+	var islogged bool
+	err = json.Unmarshal(resp.Response, &islogged)
+	if err != nil {
+		return false, fmt.Errorf("failed to unmarshal isLogged response: %w", err)
 	}
 
-	return true, nil
+	return islogged, nil
 }
@@ -0,0 +1,261 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Authenticator obtains the session tokens used by a Client to authenticate its requests.
+// The Client calls Authenticate before the first request and whenever the current token must be renewed.
+// Calls are never concurrent for a given Client.
+//
+// Implementations that need to contact DVLS must use Client.PublicRequestWithContext, since
+// RequestWithContext itself requires a token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, c *Client) (string, error)
+}
+
+// AppKeyAuthenticator authenticates with an application key and secret. This is the recommended
+// authentication method.
+type AppKeyAuthenticator struct {
+	AppKey    string
+	AppSecret string
+}
+
+// UserPasswordAuthenticator authenticates with a DVLS username and password.
+// Accounts protected by two-factor authentication require TwoFactor to answer the challenges.
+type UserPasswordAuthenticator struct {
+	Username  string
+	Password  string
+	TwoFactor TwoFactorPrompt
+}
+
+// TwoFactorChallenge describes a two-factor authentication step requested by DVLS during a login.
+type TwoFactorChallenge struct {
+	// Result is the login result that triggered the challenge, such as ServerLoginTwoFactorIsRequired,
+	// ServerLoginTwoFactorSecondStepIsRequired or ServerLoginTwoFactorSmsSended.
+	Result  ServerLoginResult
+	Message string
+}
+
+// TwoFactorPrompt returns the code answering a two-factor challenge, for example by asking the user.
+type TwoFactorPrompt func(ctx context.Context, challenge TwoFactorChallenge) (string, error)
+
+// TokenAuthenticator authenticates with a pre-issued session token. The token cannot be renewed, so
+// requests fail once the server stops accepting it.
+type TokenAuthenticator struct {
+	Token string
+}
+
+// ErrEmptyToken is returned when an Authenticator succeeds without returning a session token.
+var ErrEmptyToken = errors.New("authentication returned an empty token")
+
+type loginResponse struct {
+	TokenId string
+	Result  *ServerLoginResult
+	Message string
+}
+
+type userLoginRequest struct {
+	UserLoginInfo struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"userLoginInfo"`
+	LoginParameters struct {
+		Client string `json:"client"`
+	} `json:"loginParameters"`
+	TwoFactorInfo *userLoginTwoFactorInfo `json:"twoFactorInfo,omitempty"`
+}
+
+type userLoginTwoFactorInfo struct {
+	Code string `json:"code"`
+}
+
+type userLoginResponse struct {
+	Data struct {
+		Message string
+		Result  ServerLoginResult
+		TokenId string
+	}
+}
+
+const (
+	loginEndpoint     string = "/api/v1/login"
+	userLoginEndpoint string = "/api/login/partial"
+)
+
+const loginContentType = "application/x-www-form-urlencoded"
+
+// maxTwoFactorSteps bounds the number of challenges answered during a single login.
+const maxTwoFactorSteps = 3
+
+// isTwoFactorChallenge reports whether a login result asks for a two-factor code.
+func isTwoFactorChallenge(result ServerLoginResult) bool {
+	switch result {
+	case ServerLoginTwoFactorIsRequired, ServerLoginTwoFactorSecondStepIsRequired, ServerLoginTwoFactorSmsSended:
+		return true
+	default:
+		return false
+	}
+}
+
+// Authenticate implements the Authenticator interface.
+func (a AppKeyAuthenticator) Authenticate(ctx context.Context, c *Client) (string, error) {
+	form := url.Values{}
+	form.Set("AppKey", a.AppKey)
+	form.Set("AppSecret", a.AppSecret)
+	loginBody := form.Encode()
+
+	reqUrl, err := url.JoinPath(c.baseUri, loginEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to build login url: %w", err)
+	}
+
+	resp, err := c.PublicRequestWithContext(ctx, reqUrl, http.MethodPost, loginContentType, bytes.NewBufferString(loginBody))
+	if err != nil {
+		if loginErr := loginErrorFromRequestError(err); loginErr != nil {
+			return "", loginErr
+		}
+		return "", fmt.Errorf("error while submitting login request: %w", err)
+	}
+
+	var loginResponse loginResponse
+	err = json.Unmarshal(resp.Response, &loginResponse)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	if loginErr := loginResponse.loginError(); loginErr != nil {
+		return "", loginErr
+	}
+
+	return loginResponse.TokenId, nil
+}
+
+// loginError returns the LoginError described by the response, or nil if the login succeeded.
+// Responses without a result code are successful when they contain a token.
+func (r loginResponse) loginError() *LoginError {
+	if r.Result == nil {
+		if r.TokenId != "" {
+			return nil
+		}
+		return &LoginError{Result: ServerLoginError, Message: r.Message}
+	}
+
+	if *r.Result != ServerLoginSuccess {
+		return &LoginError{Result: *r.Result, Message: r.Message}
+	}
+
+	return nil
+}
+
+// loginErrorFromRequestError returns the LoginError described by the body of a rejected login request,
+// or nil if the body does not contain a login result. The user/password login endpoint nests its result
+// in a data object.
+func loginErrorFromRequestError(err error) *LoginError {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || len(reqErr.RawBody()) == 0 {
+		return nil
+	}
+
+	var body struct {
+		loginResponse
+		Data loginResponse
+	}
+	if json.Unmarshal(reqErr.RawBody(), &body) != nil {
+		return nil
+	}
+
+	loginResponse := body.loginResponse
+	if loginResponse.Result == nil {
+		loginResponse = body.Data
+	}
+	if loginResponse.Result == nil {
+		return nil
+	}
+
+	return loginResponse.loginError()
+}
+
+// Authenticate implements the Authenticator interface.
+// Two-factor challenges are answered through the TwoFactor prompt.
+func (a UserPasswordAuthenticator) Authenticate(ctx context.Context, c *Client) (string, error) {
+	var twoFactorCode string
+
+	for step := 0; ; step++ {
+		loginResponse, err := a.login(ctx, c, twoFactorCode)
+		if err != nil {
+			return "", err
+		}
+
+		result := loginResponse.Data.Result
+		if result == ServerLoginSuccess {
+			return loginResponse.Data.TokenId, nil
+		}
+
+		loginErr := &LoginError{Result: result, Message: loginResponse.Data.Message}
+		if !isTwoFactorChallenge(result) || a.TwoFactor == nil || step >= maxTwoFactorSteps {
+			return "", loginErr
+		}
+
+		twoFactorCode, err = a.TwoFactor(ctx, TwoFactorChallenge{Result: result, Message: loginResponse.Data.Message})
+		if err != nil {
+			return "", fmt.Errorf("two-factor prompt failed: %w", err)
+		}
+	}
+}
+
+// login submits the user credentials, along with the two-factor code when answering a challenge.
+func (a UserPasswordAuthenticator) login(ctx context.Context, c *Client, twoFactorCode string) (userLoginResponse, error) {
+	var loginRequest userLoginRequest
+	loginRequest.UserLoginInfo.Username = a.Username
+	loginRequest.UserLoginInfo.Password = a.Password
+	loginRequest.LoginParameters.Client = "Cli"
+	if twoFactorCode != "" {
+		loginRequest.TwoFactorInfo = &userLoginTwoFactorInfo{Code: twoFactorCode}
+	}
+
+	loginBody, err := json.Marshal(loginRequest)
+	if err != nil {
+		return userLoginResponse{}, fmt.Errorf("failed to marshal login body: %w", err)
+	}
+
+	reqUrl, err := url.JoinPath(c.baseUri, userLoginEndpoint)
+	if err != nil {
+		return userLoginResponse{}, fmt.Errorf("failed to build login url: %w", err)
+	}
+
+	resp, err := c.PublicRequestWithContext(ctx, reqUrl, http.MethodPost, defaultContentType, bytes.NewBuffer(loginBody))
+	if err != nil {
+		if loginErr := loginErrorFromRequestError(err); loginErr != nil {
+			return userLoginResponse{}, loginErr
+		}
+		return userLoginResponse{}, fmt.Errorf("error while submitting login request: %w", err)
+	}
+
+	var loginResponse userLoginResponse
+	err = json.Unmarshal(resp.Response, &loginResponse)
+	if err != nil {
+		return userLoginResponse{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return loginResponse, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a TokenAuthenticator) Authenticate(ctx context.Context, c *Client) (string, error) {
+	return a.Token, nil
+}
+
+// PublicRequestWithContext sends a request that does not require a session token, such as a login request,
+// and returns a Response that contains the HTTP response body in bytes, the result code and result message.
+// The provided context can be used to cancel the request.
+func (c *Client) PublicRequestWithContext(ctx context.Context, url string, reqMethod string, contentType string, reqBody io.Reader) (Response, error) {
+	return c.sendRequestWithContext(ctx, "", url, reqMethod, contentType, reqBody)
+}
@@ -0,0 +1,210 @@
+package dvls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppKeyAuthenticator(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "test-key", r.PostForm.Get("AppKey"))
+		assert.Equal(t, "test-secret", r.PostForm.Get("AppSecret"))
+		assert.Empty(t, r.Header.Get("tokenId"))
+		w.Write([]byte(`{"result":1,"tokenId":"app-token"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClientWithAuthenticator(context.Background(), server.URL, AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", client.tokens.current())
+}
+
+func TestUserPasswordAuthenticator(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login/partial", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			UserLoginInfo struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+			} `json:"userLoginInfo"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "user", body.UserLoginInfo.Username)
+
+		if body.UserLoginInfo.Password != "password" {
+			w.Write([]byte(`{"data":{"result":2,"message":"Invalid username or password"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"result":1,"tokenId":"user-token"}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClientWithAuthenticator(context.Background(), server.URL, UserPasswordAuthenticator{Username: "user", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-token", client.tokens.current())
+
+	_, err = NewClientWithAuthenticator(context.Background(), server.URL, UserPasswordAuthenticator{Username: "user", Password: "wrong"})
+	assert.ErrorContains(t, err, "InvalidUserNamePassword")
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("login endpoint must not be called with a pre-issued token")
+	})
+	var tokenSent string
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		tokenSent = r.Header.Get("tokenId")
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClientWithAuthenticator(context.Background(), server.URL, TokenAuthenticator{Token: "ci-token"})
+	require.NoError(t, err)
+
+	_, err = client.Vaults.Get("test")
+	require.NoError(t, err)
+	assert.Equal(t, "ci-token", tokenSent)
+
+	_, err = NewClientWithAuthenticator(context.Background(), server.URL, TokenAuthenticator{})
+	assert.ErrorIs(t, err, ErrEmptyToken)
+}
+
+type countingAuthenticator struct {
+	calls int
+}
+
+func (a *countingAuthenticator) Authenticate(ctx context.Context, c *Client) (string, error) {
+	a.calls++
+	return "custom-token", nil
+}
+
+func TestCustomAuthenticator(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("tokenId") != "custom-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	authenticator := &countingAuthenticator{}
+	client, err := NewClientWithAuthenticator(context.Background(), server.URL, authenticator, WithLazyLogin())
+	require.NoError(t, err)
+	assert.Equal(t, 0, authenticator.calls)
+
+	_, err = client.Vaults.Get("test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, authenticator.calls)
+}
+
+func TestNewClientWithAuthenticator_Required(t *testing.T) {
+	_, err := NewClientWithAuthenticator(context.Background(), "http://localhost", nil)
+	assert.Error(t, err)
+}
+
+func newTwoFactorServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login/partial", func(w http.ResponseWriter, r *http.Request) {
+		var body userLoginRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch {
+		case body.TwoFactorInfo == nil:
+			w.Write([]byte(`{"data":{"result":21,"message":"Two factor is required"}}`))
+		case body.TwoFactorInfo.Code == "123456":
+			w.Write([]byte(`{"data":{"result":1,"tokenId":"2fa-token"}}`))
+		default:
+			w.Write([]byte(`{"data":{"result":37,"message":"Invalid code"}}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestUserPasswordAuthenticator_TwoFactor(t *testing.T) {
+	server := newTwoFactorServer(t)
+
+	var challenges []TwoFactorChallenge
+	authenticator := UserPasswordAuthenticator{
+		Username: "user",
+		Password: "password",
+		TwoFactor: func(ctx context.Context, challenge TwoFactorChallenge) (string, error) {
+			challenges = append(challenges, challenge)
+			return "123456", nil
+		},
+	}
+
+	client, err := NewClientWithAuthenticator(context.Background(), server.URL, authenticator)
+	require.NoError(t, err)
+	assert.Equal(t, "2fa-token", client.tokens.current())
+	require.Len(t, challenges, 1)
+	assert.Equal(t, ServerLoginTwoFactorIsRequired, challenges[0].Result)
+	assert.Equal(t, "Two factor is required", challenges[0].Message)
+}
+
+func TestUserPasswordAuthenticator_TwoFactorInvalidCode(t *testing.T) {
+	server := newTwoFactorServer(t)
+
+	authenticator := UserPasswordAuthenticator{
+		Username: "user",
+		Password: "password",
+		TwoFactor: func(ctx context.Context, challenge TwoFactorChallenge) (string, error) {
+			return "000000", nil
+		},
+	}
+
+	_, err := NewClientWithAuthenticator(context.Background(), server.URL, authenticator)
+	var loginErr *LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, ServerLoginTwoFactorInvalid, loginErr.Result)
+}
+
+func TestUserPasswordAuthenticator_TwoFactorWithoutPrompt(t *testing.T) {
+	server := newTwoFactorServer(t)
+
+	_, err := NewClientWithAuthenticator(context.Background(), server.URL, UserPasswordAuthenticator{Username: "user", Password: "password"})
+	var loginErr *LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, ServerLoginTwoFactorIsRequired, loginErr.Result)
+}
+
+func TestUserPasswordAuthenticator_TwoFactorPromptError(t *testing.T) {
+	server := newTwoFactorServer(t)
+	promptErr := errors.New("user cancelled")
+
+	authenticator := UserPasswordAuthenticator{
+		Username: "user",
+		Password: "password",
+		TwoFactor: func(ctx context.Context, challenge TwoFactorChallenge) (string, error) {
+			return "", promptErr
+		},
+	}
+
+	_, err := NewClientWithAuthenticator(context.Background(), server.URL, authenticator)
+	assert.ErrorIs(t, err, promptErr)
+}
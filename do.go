@@ -0,0 +1,135 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Do sends a request to a DVLS endpoint that the client does not wrap yet and decodes the JSON response
+// into a Resp. pathTemplate is relative to the base URI of the client, for example
+// /api/v1/vault/{vaultId}/entry/{id}, and its parameters are replaced by the path-escaped values of
+// pathParams. query is added to the URL when not empty, and body is sent as JSON when not nil.
+//
+// The session token is renewed as needed, as for the other requests. Failures are returned as a
+// *RequestError, and responses of the legacy API whose result code is not SaveResultSuccess as a *ResultError.
+func Do[Req, Resp any](ctx context.Context, c *Client, method string, pathTemplate string, pathParams map[string]string, query url.Values, body *Req) (Resp, error) {
+	var out Resp
+
+	reqUrl, err := c.buildURL(pathTemplate, pathParams, query)
+	if err != nil {
+		return out, err
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return out, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	resp, err := c.RequestWithContext(ctx, reqUrl, method, reqBody, RequestOptions{RawBody: true})
+	if err != nil {
+		return out, err
+	}
+
+	data := bytes.TrimSpace(resp.Response)
+	if len(data) == 0 {
+		return out, nil
+	}
+
+	// Only objects may carry the result code of the legacy API. A result of 0 is SaveResultError, so the
+	// code is checked whenever the key is present.
+	if data[0] == '{' {
+		var envelope struct {
+			Result  *uint8 `json:"result"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(data, &envelope); err == nil && envelope.Result != nil {
+			result := Response{Result: *envelope.Result, Message: envelope.Message}
+			if err := result.CheckRespSaveResult(); err != nil {
+				return out, err
+			}
+		}
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return out, nil
+}
+
+// DoRaw is the variant of Do for binary bodies, such as documents. body is sent as is with the given
+// content type, or application/json when empty, and the response body is returned without being decoded.
+func DoRaw(ctx context.Context, c *Client, method string, pathTemplate string, pathParams map[string]string, query url.Values, contentType string, body io.Reader) ([]byte, error) {
+	reqUrl, err := c.buildURL(pathTemplate, pathParams, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.RequestWithContext(ctx, reqUrl, method, body, RequestOptions{ContentType: contentType, RawBody: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Response, nil
+}
+
+// buildURL returns the URL of the endpoint described by pathTemplate, pathParams and query.
+func (c *Client) buildURL(pathTemplate string, pathParams map[string]string, query url.Values) (string, error) {
+	path, err := expandPathTemplate(pathTemplate, pathParams)
+	if err != nil {
+		return "", err
+	}
+
+	reqUrl, err := url.JoinPath(c.baseUri, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to build url: %w", err)
+	}
+
+	if len(query) > 0 {
+		reqUrl += "?" + query.Encode()
+	}
+
+	return reqUrl, nil
+}
+
+// expandPathTemplate replaces the {name} parameters of pathTemplate with the path-escaped values of params.
+func expandPathTemplate(pathTemplate string, params map[string]string) (string, error) {
+	var path strings.Builder
+	rest := pathTemplate
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			path.WriteString(rest)
+			return path.String(), nil
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated path parameter in %q", pathTemplate)
+		}
+		end += start
+
+		name := rest[start+1 : end]
+		value, ok := params[name]
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing path parameter %q", name)
+		}
+		// Dot segments would be resolved when joining the URL and reach another endpoint.
+		if value == "." || value == ".." {
+			return "", fmt.Errorf("invalid path parameter %q: %q", name, value)
+		}
+
+		path.WriteString(rest[:start])
+		path.WriteString(url.PathEscape(value))
+		rest = rest[end+1:]
+	}
+}
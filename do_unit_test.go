@@ -0,0 +1,99 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTag struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestDo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/vault/{vaultId}/tag", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vault 1/2", r.PathValue("vaultId"))
+		assert.Equal(t, "prod", r.URL.Query().Get("name"))
+		w.Write([]byte(`[{"id":"tag-1","name":"prod"}]`))
+	})
+	mux.HandleFunc("POST /api/v1/vault/{vaultId}/tag", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var tag testTag
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&tag))
+		tag.Id = "tag-2"
+		json.NewEncoder(w).Encode(tag)
+	})
+
+	client := newTestClient(t, mux)
+	params := map[string]string{"vaultId": "vault 1/2"}
+
+	tags, err := Do[any, []testTag](context.Background(), client, http.MethodGet, "/api/v1/vault/{vaultId}/tag", params, url.Values{"name": {"prod"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []testTag{{Id: "tag-1", Name: "prod"}}, tags)
+
+	tag, err := Do[testTag, testTag](context.Background(), client, http.MethodPost, "/api/v1/vault/{vaultId}/tag", params, nil, &testTag{Name: "dev"})
+	require.NoError(t, err)
+	assert.Equal(t, testTag{Id: "tag-2", Name: "dev"}, tag)
+}
+
+func TestDo_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/connections/partial/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":3,"message":"access denied"}`))
+	})
+	mux.HandleFunc("GET /api/connections/partial/{id}/denied", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":0,"message":"denied"}`))
+	})
+	mux.HandleFunc("GET /api/v1/vault/{vaultId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := Do[any, map[string]any](context.Background(), client, http.MethodGet, "/api/connections/partial/{id}", map[string]string{"id": "entry-1"}, nil, nil)
+	var resultErr *ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, "access denied", resultErr.Message)
+
+	_, err = Do[any, map[string]any](context.Background(), client, http.MethodGet, "/api/connections/partial/{id}/denied", map[string]string{"id": "entry-1"}, nil, nil)
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, SaveResultError, resultErr.Result)
+	assert.Equal(t, "denied", resultErr.Message)
+
+	_, err = Do[any, Vault](context.Background(), client, http.MethodGet, "/api/v1/vault/{vaultId}", map[string]string{"vaultId": "missing"}, nil, nil)
+	assert.True(t, IsNotFound(err))
+
+	_, err = Do[any, Vault](context.Background(), client, http.MethodGet, "/api/v1/vault/{vaultId}", nil, nil, nil)
+	assert.EqualError(t, err, `missing path parameter "vaultId"`)
+
+	_, err = Do[any, Vault](context.Background(), client, http.MethodGet, "/api/v1/vault/{vaultId}", map[string]string{"vaultId": ".."}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestDoRaw(t *testing.T) {
+	content := []byte{0x00, 0xff, 0x10, 0x80}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/attachment/{id}/document", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, content, body)
+		w.Write(body)
+	})
+
+	client := newTestClient(t, mux)
+
+	body, err := DoRaw(context.Background(), client, http.MethodPost, "/api/attachment/{id}/document", map[string]string{"id": "attachment-1"}, nil, "application/octet-stream", bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, content, body)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Response represents an HTTP response from the DVLS API. Contains the response body in bytes, the result code
@@ -15,20 +16,82 @@ type Response struct {
 	Response []byte `json:"-"`
 	Result   uint8
 	Message  string
+
+	// hasResult reports whether the response body carried a result code.
+	hasResult bool
 }
 
 type RequestError struct {
 	Url        string
 	StatusCode int
-	Body       []byte
-	Err        error
+	// Body is the response body sent with an unsuccessful status code, with the values of secret fields
+	// redacted. Bodies that are not JSON, or that exceed maxErrorBodySize, are entirely redacted.
+	// Use RawBody to access the body as received.
+	Body []byte
+	Err  error
+
+	header  http.Header
+	rawBody []byte
 }
 
+// maxErrorBodySize caps the size of the response body kept in a RequestError.
+const maxErrorBodySize = 64 << 10
+
 const defaultContentType string = "application/json"
 
 type RequestOptions struct {
+	// ContentType is the content type of the request body, application/json when empty.
 	ContentType string
 	RawBody     bool
+
+	// into is decoded from the JSON response body, as a stream unless the body is kept for the logger or
+	// the hooks, in which case Response.Response is left empty. Responses of the legacy API are decoded
+	// into a struct embedding legacyResult, whose result code and message are set on the Response.
+	into any
+}
+
+// legacyResult is the result code and message sent with the responses of the legacy API. It is embedded
+// in the structs those responses are decoded into, so that they are decoded in a single pass.
+type legacyResult struct {
+	Result  *uint8 `json:"result"`
+	Message string `json:"message"`
+}
+
+func (r legacyResult) saveResult() legacyResult {
+	return r
+}
+
+// legacyResponse is implemented by the structs embedding legacyResult.
+type legacyResponse interface {
+	saveResult() legacyResult
+}
+
+// legacyData is a response of the legacy API whose data is decoded once the result code was checked,
+// since failed results may be sent with data of another type, such as an empty string.
+type legacyData struct {
+	legacyResult
+	Data json.RawMessage `json:"data"`
+}
+
+// decode decodes the data of the response into v. Missing data leaves v unchanged.
+func (r legacyData) decode(v any) error {
+	if len(r.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response data: %w", err)
+	}
+
+	return nil
+}
+
+// setResult sets the result code and message of the response.
+func (r *Response) setResult(result legacyResult) {
+	if result.Result != nil {
+		r.Result, r.hasResult = *result.Result, true
+	}
+	r.Message = result.Message
 }
 
 func (e RequestError) Error() string {
@@ -39,6 +102,29 @@ func (e RequestError) Error() string {
 	return fmt.Sprintf("error while submitting request on url %s. error: %s", e.Url, e.Err.Error())
 }
 
+// Format implements the fmt.Formatter interface so that the raw body is never printed. The %+v verb
+// includes the redacted body.
+func (e RequestError) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", e.Error())
+	case verb == 'v' && f.Flag('+') && len(e.Body) > 0:
+		fmt.Fprintf(f, "%s, body: %s", e.Error(), e.Body)
+	default:
+		io.WriteString(f, e.Error())
+	}
+}
+
+// RawBody returns the response body as received, up to maxErrorBodySize bytes. It may contain secrets.
+func (e RequestError) RawBody() []byte {
+	return e.rawBody
+}
+
+// Unwrap returns the underlying error.
+func (e RequestError) Unwrap() error {
+	return e.Err
+}
+
 // IsNotFound reports whether the error is a DVLS RequestError with an HTTP 404 status code.
 func IsNotFound(err error) bool {
 	var reqErr *RequestError
@@ -56,32 +142,92 @@ func (c *Client) Request(url string, reqMethod string, reqBody io.Reader, option
 }
 
 // RequestWithContext returns a Response that contains the HTTP response body in bytes, the result code and result message.
+// The session token is renewed when it is due for renewal or when the server rejects it, in which case the
+// request is sent again once.
 // The provided context can be used to cancel the request.
 func (c *Client) RequestWithContext(ctx context.Context, url string, reqMethod string, reqBody io.Reader, options ...RequestOptions) (Response, error) {
-	islogged, err := c.isLoggedWithContext(ctx)
+	var opts RequestOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	if c.tokens.isClosed() {
+		return Response{}, &RequestError{Err: ErrClientClosed, Url: url}
+	}
+
+	body, err := readRequestBody(reqBody)
 	if err != nil {
-		return Response{}, &RequestError{Err: fmt.Errorf("failed to fetch login status: %w", err), Url: url}
+		return Response{}, &RequestError{Err: fmt.Errorf("failed to read request body: %w", err), Url: url}
 	}
-	if !islogged {
-		err := c.loginWithContext(ctx)
-		if err != nil {
-			return Response{}, &RequestError{Err: fmt.Errorf("failed to refresh login token: %w", err), Url: url}
-		}
+
+	contentType := defaultContentType
+	if opts.ContentType != "" {
+		contentType = opts.ContentType
 	}
 
-	var opts RequestOptions
-	if len(options) > 0 {
-		opts = options[0]
+	token, err := c.ensureToken(ctx)
+	if err != nil {
+		return Response{}, &RequestError{Err: fmt.Errorf("failed to refresh login token: %w", err), Url: url}
+	}
+
+	resp, err := c.sendRequestWithContext(ctx, token, url, reqMethod, contentType, newBodyReader(body), opts)
+	if !isAuthenticationExpired(resp, err) {
+		return resp, err
 	}
 
-	resp, err := c.rawRequestWithContext(ctx, url, reqMethod, defaultContentType, reqBody, opts)
+	err = c.renewToken(ctx, token)
 	if err != nil {
-		return Response{}, err
+		return Response{}, &RequestError{Err: fmt.Errorf("failed to refresh login token: %w", err), Url: url}
 	}
-	return resp, nil
+
+	return c.sendRequestWithContext(ctx, c.tokens.current(), url, reqMethod, contentType, newBodyReader(body), opts)
 }
 
 func (c *Client) rawRequestWithContext(ctx context.Context, url string, reqMethod string, contentType string, reqBody io.Reader, options ...RequestOptions) (Response, error) {
+	return c.sendRequestWithContext(ctx, c.tokens.current(), url, reqMethod, contentType, reqBody, options...)
+}
+
+// sendAttemptWithContext sends a single request authenticated with the given token, calling the client hooks,
+// logger and metrics recorder around it.
+func (c *Client) sendAttemptWithContext(ctx context.Context, token string, url string, reqMethod string, contentType string, reqBody io.Reader, options ...RequestOptions) (Response, error) {
+	if len(c.hooks) == 0 && c.logger == nil && c.metrics == nil {
+		resp, _, err := c.doAttemptWithContext(ctx, token, url, reqMethod, contentType, reqBody, options...)
+		return resp, err
+	}
+
+	var loggedBody []byte
+	if c.logBodies(ctx) && reqBody != nil {
+		body, err := readRequestBody(reqBody)
+		if err != nil {
+			return Response{}, &RequestError{Err: fmt.Errorf("failed to read request body: %w", err), Url: url}
+		}
+		loggedBody = body
+		reqBody = newBodyReader(body)
+	}
+
+	c.beforeRequest(ctx, RequestInfo{Method: reqMethod, URL: url})
+
+	start := time.Now()
+	resp, statusCode, err := c.doAttemptWithContext(ctx, token, url, reqMethod, contentType, reqBody, options...)
+	duration := time.Since(start)
+
+	c.afterResponse(ctx, ResponseInfo{
+		Method:     reqMethod,
+		URL:        url,
+		StatusCode: statusCode,
+		Duration:   duration,
+		Response:   resp,
+		Err:        err,
+	})
+	c.logAttempt(ctx, reqMethod, url, loggedBody, statusCode, duration, resp, err)
+	c.recordRequest(reqMethod, url, statusCode, duration, resp, err)
+
+	return resp, err
+}
+
+// doAttemptWithContext sends a single request authenticated with the given token and returns the parsed
+// response along with the HTTP status code, which is 0 when no response was received.
+func (c *Client) doAttemptWithContext(ctx context.Context, token string, url string, reqMethod string, contentType string, reqBody io.Reader, options ...RequestOptions) (Response, int, error) {
 	var opts RequestOptions
 	if len(options) > 0 {
 		opts = options[0]
@@ -89,44 +235,100 @@ func (c *Client) rawRequestWithContext(ctx context.Context, url string, reqMetho
 
 	req, err := http.NewRequestWithContext(ctx, reqMethod, url, reqBody)
 	if err != nil {
-		return Response{}, &RequestError{Err: fmt.Errorf("failed to make request: %w", err), Url: url}
+		return Response{}, 0, &RequestError{Err: fmt.Errorf("failed to make request: %w", err), Url: url}
 	}
 
 	req.Header.Add("Content-Type", contentType)
-	req.Header.Add("tokenId", c.credential.token)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 
-	resp, err := c.client.Do(req)
+	release, err := c.limiter.acquire(ctx)
 	if err != nil {
-		return Response{}, &RequestError{Err: fmt.Errorf("error while submitting request: %w", err), Url: url}
+		return Response{}, 0, &RequestError{Err: fmt.Errorf("failed to wait for request limits: %w", err), Url: url}
+	}
+	defer release()
+
+	resp, err := c.do(req, token)
+	if err != nil {
+		return Response{}, 0, &RequestError{Err: fmt.Errorf("error while submitting request: %w", err), Url: url}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+		scrubbedBody := []byte(redacted)
+		if len(body) > maxErrorBodySize {
+			body = body[:maxErrorBodySize]
+		} else {
+			scrubbedBody = redactBody(body)
+		}
+
+		var statusErr error = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		// The error is built from the redacted body, since its message and details end up in logs.
+		if apiErr := parseAPIError(resp.StatusCode, scrubbedBody); apiErr != nil {
+			statusErr = apiErr
+		}
+
+		return Response{}, resp.StatusCode, &RequestError{Err: statusErr, Url: url, StatusCode: resp.StatusCode, Body: scrubbedBody, header: resp.Header, rawBody: body}
+	}
+
+	var body io.Reader = resp.Body
+	if c.maxResponseSize > 0 {
+		if resp.ContentLength > c.maxResponseSize {
+			return Response{}, resp.StatusCode, &RequestError{Err: &ResponseTooLargeError{Limit: c.maxResponseSize}, Url: url}
+		}
+		body = newMaxSizeReader(resp.Body, c.maxResponseSize)
+	}
+
+	if opts.into != nil && !c.keepsResponseBody(ctx) {
+		err = json.NewDecoder(body).Decode(opts.into)
+		if IsResponseTooLarge(err) {
+			return Response{}, resp.StatusCode, &RequestError{Err: fmt.Errorf("failed to read response body: %w", err), Url: url}
+		}
+		if err != nil {
+			return Response{}, resp.StatusCode, &RequestError{Err: fmt.Errorf("failed to unmarshal response body: %w", err), Url: url}
+		}
 
-		return Response{}, &RequestError{Err: fmt.Errorf("unexpected status code %d", resp.StatusCode), Url: url, StatusCode: resp.StatusCode, Body: body}
+		var response Response
+		if legacy, ok := opts.into.(legacyResponse); ok {
+			response.setResult(legacy.saveResult())
+		}
+
+		return response, resp.StatusCode, nil
 	}
 
 	var response Response
-	response.Response, err = io.ReadAll(resp.Body)
+	response.Response, err = io.ReadAll(body)
 	if err != nil {
-		return Response{}, &RequestError{Err: fmt.Errorf("failed to read response body: %w", err), Url: url}
+		return Response{}, resp.StatusCode, &RequestError{Err: fmt.Errorf("failed to read response body: %w", err), Url: url}
 	}
 
-	if !opts.RawBody && len(response.Response) > 0 {
-		err = json.Unmarshal(response.Response, &response)
+	if opts.into != nil {
+		err = json.Unmarshal(response.Response, opts.into)
+		if err != nil {
+			return response, resp.StatusCode, &RequestError{Err: fmt.Errorf("failed to unmarshal response body: %w", err), Url: url}
+		}
+		if legacy, ok := opts.into.(legacyResponse); ok {
+			response.setResult(legacy.saveResult())
+		}
+	} else if !opts.RawBody && len(response.Response) > 0 {
+		var result legacyResult
+		err = json.Unmarshal(response.Response, &result)
 		if err != nil {
-			return response, &RequestError{Err: fmt.Errorf("failed to unmarshal response body: %w", err), Url: url}
+			return response, resp.StatusCode, &RequestError{Err: fmt.Errorf("failed to unmarshal response body: %w", err), Url: url}
 		}
+		response.setResult(result)
 	}
 
-	return response, nil
+	return response, resp.StatusCode, nil
 }
 
+// CheckRespSaveResult returns a *ResultError when the response result code is not SaveResultSuccess.
 func (r Response) CheckRespSaveResult() error {
 	resultCode := SaveResult(r.Result)
 	if resultCode != SaveResultSuccess {
-		return fmt.Errorf("unexpected result code %d (%s) %s", resultCode, resultCode, r.Message)
+		return &ResultError{Result: resultCode, Message: r.Message}
 	}
 	return nil
 }
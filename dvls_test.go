@@ -9,8 +9,9 @@ import (
 )
 
 var (
-	testClient  Client
-	testVaultId string // Used by legacy tests (certificate, host, website)
+	testClient   *Client
+	testVaultId  string // Used by legacy tests (certificate, host, website)
+	testRecorder *Recorder
 )
 
 func TestMain(m *testing.M) {
@@ -22,6 +23,12 @@ func TestMain(m *testing.M) {
 	}
 
 	exitCode := m.Run()
+
+	if testRecorder != nil {
+		if err := testRecorder.Stop(); err != nil {
+			log.Fatal(err)
+		}
+	}
 	os.Exit(exitCode)
 }
 
@@ -38,8 +45,11 @@ func test_isLogged(t *testing.T) {
 		t.Fatalf("expected token to be valid but isLogged returned %t", islogged)
 	}
 
-	invalidClient := testClient
-	invalidClient.credential.token = "placeholder"
+	invalidClient := &Client{
+		client:  testClient.client,
+		baseUri: testClient.baseUri,
+		tokens:  tokenManager{token: "placeholder"},
+	}
 	islogged, err = invalidClient.isLogged()
 	if err != nil {
 		t.Fatal(err)
@@ -49,8 +59,35 @@ func test_isLogged(t *testing.T) {
 	}
 }
 
+// setupTestClient creates the client used by the integration tests. When TEST_CASSETTE is set, the DVLS
+// exchanges are recorded into, or replayed from, that cassette according to TEST_CASSETTE_MODE: "record",
+// "replay" or, by default, "auto".
 func setupTestClient() error {
-	c, err := NewClient(os.Getenv("TEST_USER"), os.Getenv("TEST_PASSWORD"), os.Getenv("TEST_INSTANCE"))
+	instance := os.Getenv("TEST_INSTANCE")
+
+	var opts []ClientOption
+	if cassettePath := os.Getenv("TEST_CASSETTE"); cassettePath != "" {
+		mode := RecorderModeAuto
+		switch os.Getenv("TEST_CASSETTE_MODE") {
+		case "record":
+			mode = RecorderModeRecord
+		case "replay":
+			mode = RecorderModeReplay
+		}
+
+		recorder, err := NewRecorder(cassettePath, mode, nil)
+		if err != nil {
+			return err
+		}
+		testRecorder = recorder
+		opts = append(opts, WithTransport(recorder))
+
+		if instance == "" && recorder.Mode() == RecorderModeReplay {
+			instance = "https://dvls.invalid"
+		}
+	}
+
+	c, err := NewClient(os.Getenv("TEST_USER"), os.Getenv("TEST_PASSWORD"), instance, opts...)
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,10 @@
 package dvls
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,7 +27,7 @@ func TestNewClient_Login(t *testing.T) {
 
 	client, err := NewClient("test-key", "test-secret", server.URL)
 	require.NoError(t, err)
-	assert.Equal(t, "mock-token-123", client.credential.token)
+	assert.Equal(t, "mock-token-123", client.tokens.token)
 	assert.NotNil(t, client.Entries)
 	assert.NotNil(t, client.Vaults)
 }
@@ -48,12 +50,98 @@ func TestIsLogged_False(t *testing.T) {
 	defer server.Close()
 
 	client := &Client{
-		baseUri:    server.URL,
-		client:     server.Client(),
-		credential: credentials{token: "test-token"},
+		baseUri: server.URL,
+		client:  server.Client(),
+		tokens:  tokenManager{token: "test-token"},
 	}
 
 	logged, err := client.isLogged()
 	require.NoError(t, err)
 	assert.False(t, logged)
 }
+
+func TestRequestError_RedactsBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"invalid entry","entry":{"name":"db","data":{"username":"sa","password":"hunter2"}}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseUri: server.URL, client: server.Client(), tokens: tokenManager{token: "test-token"}}
+
+	_, err := client.Request(server.URL+"/api/v1/vault", http.MethodPost, nil)
+	require.Error(t, err)
+
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.JSONEq(t, `{"message":"invalid entry","entry":{"name":"db","data":{"username":"sa","password":"[REDACTED]"}}}`, string(reqErr.Body))
+	assert.Contains(t, string(reqErr.RawBody()), "hunter2")
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q"} {
+		assert.NotContains(t, fmt.Sprintf(format, reqErr), "hunter2", format)
+		assert.NotContains(t, fmt.Sprintf(format, *reqErr), "hunter2", format)
+	}
+	assert.Contains(t, fmt.Sprintf("%+v", reqErr), `"password":"[REDACTED]"`)
+	assert.NotContains(t, fmt.Sprintf("%+v", fmt.Errorf("wrapped: %w", err)), "hunter2")
+}
+
+func TestRequestError_RedactsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"validation failed","errors":{"Password":["hunter2 is too short"],"Name":["required"]}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{baseUri: server.URL, client: server.Client(), tokens: tokenManager{token: "test-token"}}
+
+	_, err := client.Request(server.URL+"/api/v1/vault", http.MethodPost, nil)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "validation failed", apiErr.Message)
+	assert.Equal(t, map[string][]string{"Password": {redacted}, "Name": {"required"}}, apiErr.Details)
+	assert.NotContains(t, fmt.Sprintf("%+v", apiErr), "hunter2")
+}
+
+func TestRequestError_CapsBody(t *testing.T) {
+	large := `{"password":"hunter2","padding":"` + strings.Repeat("a", maxErrorBodySize) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(large))
+	}))
+	defer server.Close()
+
+	client := &Client{baseUri: server.URL, client: server.Client(), tokens: tokenManager{token: "test-token"}}
+
+	_, err := client.Request(server.URL+"/api/v1/vault", http.MethodGet, nil)
+	require.Error(t, err)
+
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, redacted, string(reqErr.Body))
+	assert.Len(t, reqErr.RawBody(), maxErrorBodySize)
+}
+
+func TestRequest_ContentType(t *testing.T) {
+	var contentTypes []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+	})
+	mux.HandleFunc("POST /api/attachment/{id}/document", func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		w.Write([]byte(`{"result":1}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	_, err := client.Request(client.baseUri+"/api/v1/vault", http.MethodPost, strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_, err = client.Request(client.baseUri+"/api/v1/vault", http.MethodPost, strings.NewReader(`a,b`), RequestOptions{ContentType: "text/csv"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.uploadAttachment(t.Context(), []byte("-----BEGIN CERTIFICATE-----"), "attachment-1"))
+	require.NoError(t, client.uploadAttachment(t.Context(), []byte{0x00, 0xff, 0x10}, "attachment-2"))
+
+	assert.Equal(t, []string{"application/json", "text/csv", "application/json", "application/json"}, contentTypes)
+}
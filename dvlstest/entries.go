@@ -0,0 +1,268 @@
+package dvlstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Devolutions/go-dvls"
+)
+
+// storedEntry is an entry of the v1 API. Its data is kept as sent, so that entry types unsupported by
+// the dvls package round-trip as well.
+type storedEntry struct {
+	Id          string          `json:"id"`
+	VaultId     string          `json:"vaultId"`
+	Name        string          `json:"name"`
+	Path        string          `json:"path"`
+	Type        string          `json:"type"`
+	SubType     string          `json:"subType"`
+	Description string          `json:"description"`
+	Tags        []string        `json:"tags"`
+	Data        json.RawMessage `json:"data"`
+	CreatedBy   string          `json:"createdBy"`
+	CreatedOn   time.Time       `json:"createdOn"`
+	ModifiedBy  string          `json:"modifiedBy"`
+	ModifiedOn  time.Time       `json:"modifiedOn"`
+}
+
+// AddEntry stores an entry in the vault with Id entry.VaultId and returns it. An Id is generated when
+// entry has none. The test fails if the vault does not exist or the entry data cannot be marshaled.
+func (s *Server) AddEntry(entry dvls.Entry) dvls.Entry {
+	s.tb.Helper()
+
+	data, err := json.Marshal(entry.Data)
+	if err != nil {
+		s.tb.Fatalf("dvlstest: cannot marshal entry data: %v", err)
+	}
+	if entry.Id == "" {
+		entry.Id = newID()
+	}
+
+	now := time.Now().UTC()
+	stored := &storedEntry{
+		Id:          entry.Id,
+		VaultId:     entry.VaultId,
+		Name:        entry.Name,
+		Path:        entry.Path,
+		Type:        entry.Type,
+		SubType:     entry.SubType,
+		Description: entry.Description,
+		Tags:        entry.Tags,
+		Data:        data,
+		CreatedBy:   DefaultUsername,
+		CreatedOn:   now,
+		ModifiedBy:  DefaultUsername,
+		ModifiedOn:  now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.vaultIndex(entry.VaultId) < 0 {
+		s.tb.Fatalf("dvlstest: vault %s does not exist", entry.VaultId)
+	}
+	s.entries = append(s.entries, stored)
+
+	return entry
+}
+
+// Entry returns the stored entry with the given vault and entry Id. It reports false when the entry does
+// not exist or its type is not supported by the dvls package.
+func (s *Server) Entry(vaultId string, entryId string) (dvls.Entry, bool) {
+	s.mu.Lock()
+	i := s.entryIndex(vaultId, entryId)
+	var body []byte
+	if i >= 0 {
+		body, _ = json.Marshal(s.entries[i])
+	}
+	s.mu.Unlock()
+
+	var entry dvls.Entry
+	if i < 0 || json.Unmarshal(body, &entry) != nil {
+		return dvls.Entry{}, false
+	}
+
+	return entry, true
+}
+
+// entryIndex returns the index of an entry in s.entries, or -1. s.mu must be held.
+func (s *Server) entryIndex(vaultId string, entryId string) int {
+	return slices.IndexFunc(s.entries, func(e *storedEntry) bool {
+		return e.VaultId == vaultId && e.Id == entryId
+	})
+}
+
+// matchesPath reports whether an entry matches the path filter of the list endpoint. Like DVLS, the
+// filter is not exact: it matches every path starting with it, case-insensitively, so that "Servers"
+// matches "Servers", "Servers\Linux" and "Servers2" alike.
+func matchesPath(entryPath string, filter string) bool {
+	return len(entryPath) >= len(filter) && strings.EqualFold(entryPath[:len(filter)], filter)
+}
+
+type entryRequest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Path        string          `json:"path"`
+	Type        string          `json:"type"`
+	SubType     string          `json:"subType"`
+	Tags        []string        `json:"tags"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// decodeEntryRequest decodes and validates the body of an entry creation or update, writing an error
+// response when it is invalid.
+func decodeEntryRequest(w http.ResponseWriter, r *http.Request, create bool) (entryRequest, bool) {
+	var req entryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return entryRequest{}, false
+	}
+
+	validation := map[string][]string{}
+	if req.Name == "" {
+		validation["name"] = []string{"The name field is required."}
+	}
+	if create && req.Type == "" {
+		validation["type"] = []string{"The type field is required."}
+	}
+	if len(validation) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"message": "One or more validation errors occurred.",
+			"errors":  validation,
+		})
+		return entryRequest{}, false
+	}
+
+	return req, true
+}
+
+func (s *Server) handleListEntries(w http.ResponseWriter, r *http.Request) {
+	vaultId := r.PathValue("vaultId")
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	if s.vaultIndex(vaultId) < 0 {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+	var entries []*storedEntry
+	for _, e := range s.entries {
+		if e.VaultId != vaultId {
+			continue
+		}
+		if query.Has("name") && e.Name != query.Get("name") {
+			continue
+		}
+		if !matchesPath(e.Path, query.Get("path")) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	resp := page(s, r, entries)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateEntry(w http.ResponseWriter, r *http.Request) {
+	vaultId := r.PathValue("vaultId")
+	req, ok := decodeEntryRequest(w, r, true)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	stored := &storedEntry{
+		Id:          newID(),
+		VaultId:     vaultId,
+		Name:        req.Name,
+		Path:        req.Path,
+		Type:        req.Type,
+		SubType:     req.SubType,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Data:        req.Data,
+		CreatedBy:   DefaultUsername,
+		CreatedOn:   now,
+		ModifiedBy:  DefaultUsername,
+		ModifiedOn:  now,
+	}
+
+	s.mu.Lock()
+	if s.vaultIndex(vaultId) < 0 {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+	s.entries = append(s.entries, stored)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]any{"id": stored.Id})
+}
+
+func (s *Server) handleGetEntry(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	i := s.entryIndex(r.PathValue("vaultId"), r.PathValue("id"))
+	var body []byte
+	if i >= 0 {
+		body, _ = json.Marshal(s.entries[i])
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, json.RawMessage(body))
+}
+
+func (s *Server) handleUpdateEntry(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeEntryRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	i := s.entryIndex(r.PathValue("vaultId"), r.PathValue("id"))
+	if i >= 0 {
+		updated := *s.entries[i]
+		updated.Name = req.Name
+		updated.Description = req.Description
+		updated.Path = req.Path
+		updated.Tags = req.Tags
+		if len(req.Data) > 0 {
+			updated.Data = req.Data
+		}
+		updated.ModifiedOn = time.Now().UTC()
+		s.entries[i] = &updated
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleDeleteEntry(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	i := s.entryIndex(r.PathValue("vaultId"), r.PathValue("id"))
+	if i >= 0 {
+		s.entries = slices.Delete(s.entries, i, i+1)
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
@@ -0,0 +1,134 @@
+package dvlstest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Devolutions/go-dvls"
+)
+
+// Fault describes a failure injected into the responses of a Server. A fault applies to the requests
+// matching its Method and Path, and replaces their response with, in order of precedence, a dropped
+// connection, an HTTP error or a legacy result code. A fault with only a Delay slows down the requests
+// and lets the server handle them normally.
+type Fault struct {
+	// Method restricts the fault to the requests with this HTTP method. Empty matches every method.
+	Method string
+	// Path restricts the fault to the requests with a matching URL path, such as /api/v1/vault/{vaultId}.
+	// Segments in braces match any single segment. Empty matches every path.
+	Path string
+	// Times is the number of requests the fault applies to. Zero applies it until ClearFaults is called.
+	Times int
+
+	// Delay is waited before responding, or until the request is canceled.
+	Delay time.Duration
+	// CloseConnection drops the connection without responding.
+	CloseConnection bool
+	// StatusCode is the HTTP status code of the response, with Body and Header.
+	StatusCode int
+	Body       string
+	Header     http.Header
+	// Result is the result code of a 200 OK response in the format of the legacy API, such as
+	// new(dvls.SaveResultAccessDenied). It is ignored when nil.
+	Result *dvls.SaveResult
+}
+
+type fault struct {
+	Fault
+	remaining int
+}
+
+// InjectFault adds a fault to the server. When several faults match a request, the first injected one
+// applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault{Fault: f, remaining: f.Times})
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// matchFault returns the fault applying to r, if any, and consumes one of its occurrences.
+// s.mu must be held.
+func (s *Server) matchFault(r *http.Request) *fault {
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+func (f *fault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+
+	pattern := strings.Split(strings.Trim(f.Path, "/"), "/")
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if !strings.EqualFold(segment, segments[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apply writes the response of the fault and reports whether the request was handled.
+func (f *fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	switch {
+	case f.CloseConnection:
+		panic(http.ErrAbortHandler)
+	case f.StatusCode != 0:
+		for key, values := range f.Header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(f.StatusCode)
+		w.Write([]byte(f.Body))
+		return true
+	case f.Result != nil:
+		writeResult(w, *f.Result, nil)
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,362 @@
+package dvlstest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Devolutions/go-dvls"
+)
+
+// The legacy endpoints exchange entries in the connection format, whose data is a JSON document
+// encoded in a string for hosts and websites, and a JSON object for certificates. Entries are kept
+// as sent and decoded on demand.
+
+type storedAttachment struct {
+	Id            string `json:"id"`
+	IdString      string `json:"idString"`
+	EntryId       string `json:"connectionID"`
+	EntryIdString string `json:"connectionIDString"`
+	Description   string `json:"description"`
+	FileName      string `json:"filename"`
+	IsPrivate     bool   `json:"isPrivate"`
+	Size          int    `json:"size"`
+	Title         string `json:"title"`
+
+	content []byte
+}
+
+// AddHost stores a host entry and returns it. An Id is generated when entry has none.
+func (s *Server) AddHost(entry dvls.EntryHost) dvls.EntryHost {
+	if entry.Id == "" {
+		entry.Id = newID()
+	}
+	if entry.ConnectionType == 0 {
+		entry.ConnectionType = dvls.ServerConnectionHost
+	}
+
+	s.addLegacyEntry(entry.Id, entry)
+
+	return entry
+}
+
+// AddWebsite stores a website entry and returns it. An Id is generated when entry has none.
+func (s *Server) AddWebsite(entry dvls.EntryWebsite) dvls.EntryWebsite {
+	if entry.Id == "" {
+		entry.Id = newID()
+	}
+	if entry.ConnectionType == 0 {
+		entry.ConnectionType = dvls.ServerConnectionWebBrowser
+	}
+
+	s.addLegacyEntry(entry.Id, entry)
+
+	return entry
+}
+
+// AddCertificate stores a certificate entry and returns it. An Id is generated when entry has none.
+// The certificate is stored as a file attachment when content is not nil, and as a URL otherwise.
+func (s *Server) AddCertificate(entry dvls.EntryCertificate, content []byte) dvls.EntryCertificate {
+	s.tb.Helper()
+
+	if entry.Id == "" {
+		entry.Id = newID()
+	}
+
+	obj := decodeObject(s.mustMarshal(entry))
+	data, _ := obj["data"].(map[string]any)
+	data["dataMode"] = dvls.EntryCertificateDataModeURL
+	if content != nil {
+		data["dataMode"] = dvls.EntryCertificateDataModeFile
+		data["documentSize"] = len(content)
+	}
+	s.addLegacyEntry(entry.Id, obj)
+
+	if content != nil {
+		s.mu.Lock()
+		s.attachments = append(s.attachments, &storedAttachment{
+			Id:        newID(),
+			EntryId:   entry.Id,
+			FileName:  entry.CertificateIdentifier,
+			IsPrivate: true,
+			Size:      len(content),
+			content:   slices.Clone(content),
+		})
+		s.mu.Unlock()
+	}
+
+	var stored dvls.EntryCertificate
+	if err := json.Unmarshal(s.mustMarshal(map[string]any{"data": obj}), &stored); err != nil {
+		s.tb.Fatalf("dvlstest: cannot decode certificate: %v", err)
+	}
+
+	return stored
+}
+
+func (s *Server) addLegacyEntry(id string, entry any) {
+	s.tb.Helper()
+
+	body := s.mustMarshal(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.legacy[id] = body
+}
+
+// mustMarshal marshals an entry added by the test, failing the test when it cannot be marshaled.
+func (s *Server) mustMarshal(value any) json.RawMessage {
+	s.tb.Helper()
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		s.tb.Fatalf("dvlstest: cannot marshal entry: %v", err)
+	}
+
+	return body
+}
+
+func decodeObject(body []byte) map[string]any {
+	var obj map[string]any
+	json.Unmarshal(body, &obj)
+	if obj == nil {
+		obj = map[string]any{}
+	}
+
+	return obj
+}
+
+// legacyData returns the decoded data of a legacy entry.
+func legacyData(obj map[string]any) map[string]any {
+	switch data := obj["data"].(type) {
+	case map[string]any:
+		return data
+	case string:
+		return decodeObject([]byte(data))
+	default:
+		return map[string]any{}
+	}
+}
+
+// legacySecret returns the password item of legacy entry data: PasswordItem for hosts and websites,
+// password for certificates.
+func legacySecret(data map[string]any) map[string]any {
+	for key, value := range data {
+		if strings.EqualFold(key, "PasswordItem") || strings.EqualFold(key, "password") {
+			if item, ok := value.(map[string]any); ok {
+				return item
+			}
+		}
+	}
+
+	return nil
+}
+
+// secretField returns the value of a field of a password item, whatever the case of its key.
+func secretField(item map[string]any, name string) any {
+	for key, value := range item {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleSaveLegacyEntry(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+		writeResult(w, dvls.SaveResultInvalidData, nil)
+		return
+	}
+
+	id, _ := obj["id"].(string)
+	vaultId, _ := obj["repositoryId"].(string)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodPut {
+		if _, ok := s.legacy[id]; !ok {
+			writeResult(w, dvls.SaveResultNotFound, nil)
+			return
+		}
+	} else if id == "" {
+		id = newID()
+		obj["id"] = id
+	} else if _, ok := s.legacy[id]; ok {
+		writeResult(w, dvls.SaveResultAlreadyExists, nil)
+		return
+	}
+	if s.vaultIndex(vaultId) < 0 {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+
+	body, err = json.Marshal(obj)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.legacy[id] = body
+
+	writeResult(w, dvls.SaveResultSuccess, obj)
+}
+
+// handleGetConnection serves both /api/connections/partial/{id} and /api/connections/{id}/document,
+// whose patterns overlap.
+func (s *Server) handleGetConnection(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.PathValue("first") == "partial":
+		s.handleGetLegacyEntry(w, r, r.PathValue("second"))
+	case r.PathValue("second") == "document":
+		s.handleGetDocument(w, r, r.PathValue("first"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleGetLegacyEntry(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	body, ok := s.legacy[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+
+	// Secrets are only returned by the sensitive-data endpoint.
+	obj := decodeObject(body)
+	data := legacyData(obj)
+	if item := legacySecret(data); item != nil {
+		for key := range item {
+			if strings.EqualFold(key, "SensitiveData") {
+				item[key] = ""
+			}
+		}
+	}
+	obj["data"] = data
+
+	writeResult(w, dvls.SaveResultSuccess, obj)
+}
+
+func (s *Server) handleDeleteLegacyEntry(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	_, ok := s.legacy[id]
+	delete(s.legacy, id)
+	s.attachments = slices.DeleteFunc(s.attachments, func(a *storedAttachment) bool { return a.EntryId == id })
+	s.mu.Unlock()
+
+	if !ok {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+
+	writeResult(w, dvls.SaveResultSuccess, nil)
+}
+
+func (s *Server) handleSensitiveData(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mu.Lock()
+	body, ok := s.legacy[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+
+	hasSensitiveData, sensitiveData := false, ""
+	if item := legacySecret(legacyData(decodeObject(body))); item != nil {
+		hasSensitiveData, _ = secretField(item, "HasSensitiveData").(bool)
+		sensitiveData, _ = secretField(item, "SensitiveData").(string)
+	}
+	secret := map[string]any{"hasSensitiveData": hasSensitiveData, "sensitiveData": sensitiveData}
+
+	// The sensitive data is a JSON document encoded in a string. Hosts and websites read their secret
+	// from passwordItem and certificates from password.
+	inner, err := json.Marshal(map[string]any{
+		"id":   id,
+		"data": map[string]any{"passwordItem": secret, "password": secret},
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeResult(w, dvls.SaveResultSuccess, string(inner))
+}
+
+func (s *Server) handleGetDocument(w http.ResponseWriter, r *http.Request, entryId string) {
+	s.mu.Lock()
+	i := slices.IndexFunc(s.attachments, func(a *storedAttachment) bool {
+		return a.EntryId == entryId && a.content != nil
+	})
+	var content []byte
+	if i >= 0 {
+		content = s.attachments[i].content
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(content)
+}
+
+func (s *Server) handleSaveAttachment(w http.ResponseWriter, r *http.Request) {
+	var attachment storedAttachment
+	if err := json.NewDecoder(r.Body).Decode(&attachment); err != nil {
+		writeResult(w, dvls.SaveResultInvalidData, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.legacy[attachment.EntryId]; !ok {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+
+	attachment.Id = newID()
+	attachment.IdString = attachment.Id
+	attachment.EntryIdString = attachment.EntryId
+	s.attachments = append(s.attachments, &attachment)
+
+	writeResult(w, dvls.SaveResultSuccess, attachment)
+}
+
+func (s *Server) handleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.attachments, func(a *storedAttachment) bool { return a.Id == r.PathValue("id") })
+	if i < 0 {
+		writeResult(w, dvls.SaveResultNotFound, nil)
+		return
+	}
+	s.attachments[i].content = content
+
+	writeResult(w, dvls.SaveResultSuccess, nil)
+}
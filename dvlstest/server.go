@@ -0,0 +1,431 @@
+// Package dvlstest provides an in-memory fake DVLS server, for testing code that depends on go-dvls
+// without a DVLS instance.
+//
+// The fake keeps its state in memory and implements the endpoints used by the dvls package: login,
+// logout and session checks, vault CRUD with pagination, entry CRUD with the name and path filters,
+// the legacy host, website and certificate endpoints, sensitive data and attachments. Faults can be
+// injected to exercise error handling, retries and re-logins.
+//
+//	server := dvlstest.NewServer(t)
+//	vault := server.AddVault(dvls.Vault{Name: "Infrastructure"})
+//
+//	client, err := server.NewClient()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer client.Close()
+package dvlstest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Devolutions/go-dvls"
+)
+
+// Default credentials accepted by a Server.
+const (
+	DefaultAppKey    = "dvlstest-app-key"
+	DefaultAppSecret = "dvlstest-app-secret"
+	DefaultUsername  = "dvlstest-user"
+	DefaultPassword  = "dvlstest-password"
+)
+
+// DefaultVersion is the DVLS version reported by a Server.
+const DefaultVersion = "2026.1.0.0"
+
+// DefaultPageSize is the number of items returned per page by the list endpoints of a Server.
+const DefaultPageSize = 25
+
+// Server is a fake DVLS server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URI of the server, to be passed to the dvls.NewClient functions.
+	URL string
+
+	tb         testing.TB
+	httpServer *httptest.Server
+	mux        *http.ServeMux
+
+	appKey     string
+	appSecret  string
+	username   string
+	password   string
+	twoFactor  string
+	version    string
+	serverName string
+	pageSize   int
+
+	mu          sync.Mutex
+	tokens      map[string]bool
+	vaults      []dvls.Vault
+	entries     []*storedEntry
+	legacy      map[string]json.RawMessage
+	attachments []*storedAttachment
+	faults      []*fault
+	requests    []Request
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAppKey sets the application key and secret accepted by the server, instead of DefaultAppKey
+// and DefaultAppSecret.
+func WithAppKey(appKey string, appSecret string) Option {
+	return func(s *Server) {
+		s.appKey = appKey
+		s.appSecret = appSecret
+	}
+}
+
+// WithUser sets the username and password accepted by the server, instead of DefaultUsername and
+// DefaultPassword.
+func WithUser(username string, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithTwoFactorCode requires users to answer a two-factor challenge with code when they log in.
+func WithTwoFactorCode(code string) Option {
+	return func(s *Server) {
+		s.twoFactor = code
+	}
+}
+
+// WithVersion sets the DVLS version reported by the server information endpoints.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithPageSize sets the number of items returned per page by the list endpoints.
+func WithPageSize(pageSize int) Option {
+	return func(s *Server) {
+		s.pageSize = pageSize
+	}
+}
+
+// NewServer starts a Server, which is closed when the test and all its subtests complete.
+func NewServer(tb testing.TB, opts ...Option) *Server {
+	tb.Helper()
+
+	s := &Server{
+		tb:         tb,
+		appKey:     DefaultAppKey,
+		appSecret:  DefaultAppSecret,
+		username:   DefaultUsername,
+		password:   DefaultPassword,
+		version:    DefaultVersion,
+		serverName: "dvlstest",
+		pageSize:   DefaultPageSize,
+		tokens:     make(map[string]bool),
+		legacy:     make(map[string]json.RawMessage),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.pageSize <= 0 {
+		tb.Fatalf("dvlstest: invalid page size %d", s.pageSize)
+	}
+
+	s.mux = http.NewServeMux()
+	s.routes()
+
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.httpServer.URL
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// Close shuts down the server. It is called automatically when the test completes.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// NewClient returns a dvls.Client logged in to the server with its application key.
+func (s *Server) NewClient(opts ...dvls.ClientOption) (*dvls.Client, error) {
+	return dvls.NewClient(s.appKey, s.appSecret, s.URL, opts...)
+}
+
+// NewUserClient returns a dvls.Client logged in to the server with its username and password.
+func (s *Server) NewUserClient(opts ...dvls.ClientOption) (*dvls.Client, error) {
+	authenticator := dvls.UserPasswordAuthenticator{Username: s.username, Password: s.password}
+	if s.twoFactor != "" {
+		code := s.twoFactor
+		authenticator.TwoFactor = func(ctx context.Context, challenge dvls.TwoFactorChallenge) (string, error) {
+			return code, nil
+		}
+	}
+
+	return dvls.NewClientWithAuthenticator(context.Background(), s.URL, authenticator, opts...)
+}
+
+// ExpireSessions invalidates every session token, as when DVLS expires idle sessions. Clients are
+// rejected with 401 Unauthorized until they log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.tokens)
+}
+
+// Request describes a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+// Requests returns the requests received by the server, in order, including the rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("POST /api/v1/login", s.handleLogin)
+	s.mux.HandleFunc("POST /api/login/partial", s.handleUserLogin)
+	s.mux.HandleFunc("GET /api/is-logged", s.handleIsLogged)
+	s.mux.HandleFunc("POST /api/v1/logout", s.authenticated(s.handleLogout))
+
+	s.mux.HandleFunc("GET /api/public-instance-information", s.handleServerInfo)
+	s.mux.HandleFunc("GET /api/private-instance-information", s.authenticated(s.handleServerInfo))
+	s.mux.HandleFunc("GET /api/configuration/timezones", s.authenticated(s.handleTimezones))
+
+	s.mux.HandleFunc("GET /api/v1/vault", s.authenticated(s.handleListVaults))
+	s.mux.HandleFunc("POST /api/v1/vault", s.authenticated(s.handleCreateVault))
+	s.mux.HandleFunc("GET /api/v1/vault/{vaultId}", s.authenticated(s.handleGetVault))
+	s.mux.HandleFunc("PUT /api/v1/vault/{vaultId}", s.authenticated(s.handleUpdateVault))
+	s.mux.HandleFunc("DELETE /api/v1/vault/{vaultId}", s.authenticated(s.handleDeleteVault))
+
+	s.mux.HandleFunc("GET /api/v1/vault/{vaultId}/entry", s.authenticated(s.handleListEntries))
+	s.mux.HandleFunc("POST /api/v1/vault/{vaultId}/entry", s.authenticated(s.handleCreateEntry))
+	s.mux.HandleFunc("GET /api/v1/vault/{vaultId}/entry/{id}", s.authenticated(s.handleGetEntry))
+	s.mux.HandleFunc("PUT /api/v1/vault/{vaultId}/entry/{id}", s.authenticated(s.handleUpdateEntry))
+	s.mux.HandleFunc("DELETE /api/v1/vault/{vaultId}/entry/{id}", s.authenticated(s.handleDeleteEntry))
+
+	s.mux.HandleFunc("POST /api/connections/partial/save", s.authenticated(s.handleSaveLegacyEntry))
+	s.mux.HandleFunc("PUT /api/connections/partial/save", s.authenticated(s.handleSaveLegacyEntry))
+	s.mux.HandleFunc("GET /api/connections/{first}/{second}", s.authenticated(s.handleGetConnection))
+	s.mux.HandleFunc("DELETE /api/connections/partial/{id}", s.authenticated(s.handleDeleteLegacyEntry))
+	s.mux.HandleFunc("POST /api/connections/partial/{id}/sensitive-data", s.authenticated(s.handleSensitiveData))
+
+	s.mux.HandleFunc("POST /api/attachment/save", s.authenticated(s.handleSaveAttachment))
+	s.mux.HandleFunc("POST /api/attachment/{id}/document", s.authenticated(s.handleUploadAttachment))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+	f := s.matchFault(r)
+	s.mu.Unlock()
+
+	if f != nil && f.apply(w, r) {
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// authenticated rejects the requests without a valid tokenId header with 401 Unauthorized.
+func (s *Server) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		ok := s.tokens[r.Header.Get("tokenId")]
+		s.mu.Unlock()
+
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.PostForm.Get("AppKey") != s.appKey || r.PostForm.Get("AppSecret") != s.appSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{
+			"result":  dvls.ServerLoginInvalidUserNamePassword,
+			"message": "invalid application key or secret",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"result": dvls.ServerLoginSuccess, "tokenId": s.newToken()})
+}
+
+func (s *Server) handleUserLogin(w http.ResponseWriter, r *http.Request) {
+	var login struct {
+		UserLoginInfo struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"userLoginInfo"`
+		TwoFactorInfo *struct {
+			Code string `json:"code"`
+		} `json:"twoFactorInfo"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respond := func(result dvls.ServerLoginResult, message string, token string) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data": map[string]any{"result": result, "message": message, "tokenId": token},
+		})
+	}
+
+	switch {
+	case login.UserLoginInfo.Username != s.username || login.UserLoginInfo.Password != s.password:
+		respond(dvls.ServerLoginInvalidUserNamePassword, "invalid username or password", "")
+	case s.twoFactor != "" && login.TwoFactorInfo == nil:
+		respond(dvls.ServerLoginTwoFactorIsRequired, "two-factor authentication is required", "")
+	case s.twoFactor != "" && login.TwoFactorInfo.Code != s.twoFactor:
+		respond(dvls.ServerLoginTwoFactorUserIsDenied, "invalid two-factor code", "")
+	default:
+		respond(dvls.ServerLoginSuccess, "", s.newToken())
+	}
+}
+
+func (s *Server) handleIsLogged(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ok := s.tokens[r.Header.Get("tokenId")]
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, ok)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	delete(s.tokens, r.Header.Get("tokenId"))
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) handleServerInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"result": dvls.SaveResultSuccess,
+		"data": map[string]any{
+			"accessUri":          s.URL,
+			"selectedTimeZoneId": "UTC",
+			"serverName":         s.serverName,
+			"version":            s.version,
+			"systemMessage":      "",
+		},
+	})
+}
+
+func (s *Server) handleTimezones(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"result": dvls.SaveResultSuccess,
+		"data": []map[string]any{{
+			"id":                         "UTC",
+			"displayName":                "(UTC) Coordinated Universal Time",
+			"standardName":               "Coordinated Universal Time",
+			"daylightName":               "Coordinated Universal Time",
+			"baseUtcOffset":              "00:00:00",
+			"adjustmentRules":            []any{},
+			"supportsDaylightSavingTime": false,
+		}},
+	})
+}
+
+func (s *Server) newToken() string {
+	token := newID()
+
+	s.mu.Lock()
+	s.tokens[token] = true
+	s.mu.Unlock()
+
+	return token
+}
+
+// page returns the list response holding the page of items requested by the page and pageSize query
+// parameters.
+func page[T any](s *Server, r *http.Request, items []T) map[string]any {
+	pageSize := s.pageSize
+	if size, err := parsePositive(r.URL.Query().Get("pageSize")); err == nil {
+		pageSize = size
+	}
+	current := 1
+	if n, err := parsePositive(r.URL.Query().Get("page")); err == nil {
+		current = n
+	}
+
+	start := min((current-1)*pageSize, len(items))
+	end := min(start+pageSize, len(items))
+
+	return map[string]any{
+		"data":        slices.Clone(items[start:end]),
+		"currentPage": current,
+		"pageSize":    pageSize,
+		"totalCount":  len(items),
+		"totalPage":   (len(items) + pageSize - 1) / pageSize,
+	}
+}
+
+func parsePositive(value string) (int, error) {
+	var n int
+	if _, err := fmt.Sscan(value, &n); err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%d is not positive", n)
+	}
+
+	return n, nil
+}
+
+// newID returns a random GUID, formatted like the DVLS identifiers.
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes an error body in the format of the v1 API.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]any{"message": message})
+}
+
+// writeResult writes a response of the legacy API, which reports its outcome as a result code.
+func writeResult(w http.ResponseWriter, result dvls.SaveResult, data any) {
+	body := map[string]any{"result": result}
+	if data != nil {
+		body["data"] = data
+	}
+	if result != dvls.SaveResultSuccess {
+		body["message"] = result.String()
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
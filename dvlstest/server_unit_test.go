@@ -0,0 +1,458 @@
+package dvlstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Devolutions/go-dvls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, server *Server, opts ...dvls.ClientOption) *dvls.Client {
+	t.Helper()
+
+	client, err := server.NewClient(opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func countRequests(server *Server, method string, path string) int {
+	count := 0
+	for _, req := range server.Requests() {
+		if req.Method == method && req.Path == path {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestServer_Login(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+
+	assert.Equal(t, 1, countRequests(server, http.MethodPost, "/api/v1/login"))
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, 1, countRequests(server, http.MethodPost, "/api/v1/logout"))
+}
+
+func TestServer_LoginInvalidCredentials(t *testing.T) {
+	server := NewServer(t, WithAppKey("key", "secret"))
+
+	_, err := dvls.NewClient("key", "wrong", server.URL)
+
+	var loginErr *dvls.LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, dvls.ServerLoginInvalidUserNamePassword, loginErr.Result)
+}
+
+func TestServer_ServerVersionCheck(t *testing.T) {
+	server := NewServer(t, WithVersion("2025.3.2.0"))
+
+	_, err := server.NewClient(dvls.WithServerVersionCheck())
+	assert.True(t, dvls.IsUnsupportedServer(err))
+	assert.Zero(t, countRequests(server, http.MethodPost, "/api/v1/login"))
+
+	client, err := server.NewClient(dvls.WithLazyLogin())
+	require.NoError(t, err)
+	probe, err := client.Probe(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, dvls.CompatibilityUnsupported, probe.Compatibility)
+}
+
+func TestServer_UserLoginTwoFactor(t *testing.T) {
+	server := NewServer(t, WithUser("alice", "pa55word"), WithTwoFactorCode("123456"))
+
+	client, err := server.NewUserClient()
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = dvls.NewClientWithAuthenticator(context.Background(), server.URL,
+		dvls.UserPasswordAuthenticator{Username: "alice", Password: "pa55word"})
+	var loginErr *dvls.LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, dvls.ServerLoginTwoFactorIsRequired, loginErr.Result)
+}
+
+func TestServer_Vaults(t *testing.T) {
+	server := NewServer(t, WithPageSize(2))
+	for i := range 5 {
+		server.AddVault(dvls.Vault{Name: fmt.Sprintf("vault-%d", i)})
+	}
+	client := newClient(t, server)
+
+	vaults, err := client.Vaults.List()
+	require.NoError(t, err)
+	require.Len(t, vaults, 5)
+	assert.Equal(t, "vault-4", vaults[4].Name)
+	assert.Equal(t, 3, countRequests(server, http.MethodGet, "/api/v1/vault"))
+
+	page, err := client.Vaults.ListPage(context.Background(), dvls.PageOptions{Page: 2, PageSize: 3})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "vault-3", page.Items[0].Name)
+	assert.Equal(t, 5, page.TotalCount)
+	assert.Equal(t, 2, page.TotalPage)
+
+	vault, err := client.Vaults.New(dvls.Vault{Name: "created", ContentType: dvls.VaultContentTypeDefault})
+	require.NoError(t, err)
+	assert.NotEmpty(t, vault.Id)
+	assert.Equal(t, dvls.VaultContentTypeEverything, vault.ContentType)
+
+	vault.Description = "updated"
+	_, err = client.Vaults.Update(vault)
+	require.NoError(t, err)
+	stored, ok := server.Vault(vault.Id)
+	require.True(t, ok)
+	assert.Equal(t, "updated", stored.Description)
+
+	require.NoError(t, client.Vaults.Delete(vault.Id))
+	_, err = client.Vaults.Get(vault.Id)
+	assert.True(t, dvls.IsNotFound(err))
+
+	_, err = client.Vaults.New(dvls.Vault{})
+	var apiErr *dvls.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Contains(t, apiErr.Details, "name")
+}
+
+func TestServer_Entries(t *testing.T) {
+	server := NewServer(t, WithPageSize(2))
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	client := newClient(t, server)
+
+	password := "s3cret"
+	id, err := client.Entries.Credential.New(dvls.Entry{
+		VaultId: vault.Id,
+		Name:    "db",
+		Path:    "Servers",
+		Type:    dvls.EntryCredentialType,
+		SubType: dvls.EntryCredentialSubTypeDefault,
+		Data:    dvls.EntryCredentialDefaultData{Username: "admin", Password: password},
+	})
+	require.NoError(t, err)
+
+	entry, err := client.Entries.Credential.GetById(vault.Id, id)
+	require.NoError(t, err)
+	data, ok := entry.GetCredentialDefaultData()
+	require.True(t, ok)
+	assert.Equal(t, password, data.Password)
+
+	entry.Description = "updated"
+	entry, err = client.Entries.Credential.Update(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", entry.Description)
+
+	require.NoError(t, client.Entries.Credential.Delete(entry))
+	_, err = client.Entries.Credential.GetById(vault.Id, id)
+	assert.True(t, dvls.IsNotFound(err))
+}
+
+func TestServer_EntriesPathFilter(t *testing.T) {
+	server := NewServer(t)
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	for _, path := range []string{"", "Servers", `Servers\Linux`, "Servers2"} {
+		server.AddEntry(dvls.Entry{
+			VaultId: vault.Id,
+			Name:    "entry",
+			Path:    path,
+			Type:    dvls.EntryFolderType,
+			SubType: dvls.EntryFolderSubTypeFolder,
+			Data:    dvls.EntryFolderData{},
+		})
+	}
+
+	assert.True(t, matchesPath("Servers2", "Servers"))
+	assert.True(t, matchesPath(`servers\Linux`, "Servers"))
+	assert.False(t, matchesPath("Serv", "Servers"))
+
+	client := newClient(t, server)
+
+	path := "Servers"
+	entries, err := client.Entries.Folder.GetEntries(vault.Id, dvls.GetEntriesOptions{Path: &path})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	entry, err := client.Entries.Folder.GetByName(vault.Id, "entry", dvls.GetByNameOptions{Path: &path})
+	require.NoError(t, err)
+	assert.Equal(t, "Servers", entry.Path)
+
+	root := ""
+	entries, err = client.Entries.Folder.GetEntries(vault.Id, dvls.GetEntriesOptions{Path: &root})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestServer_Host(t *testing.T) {
+	server := NewServer(t)
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	password := "hostpass"
+	host := server.AddHost(dvls.EntryHost{
+		VaultId:     vault.Id,
+		EntryName:   "host",
+		HostDetails: dvls.EntryHostAuthDetails{Username: "admin", Host: "10.0.0.1", Password: &password},
+	})
+	client := newClient(t, server)
+
+	entry, err := client.Entries.Host.Get(host.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "host", entry.EntryName)
+	assert.Equal(t, "admin", entry.HostDetails.Username)
+	assert.Equal(t, "10.0.0.1", entry.HostDetails.Host)
+	assert.Nil(t, entry.HostDetails.Password)
+
+	entry, err = client.Entries.Host.GetHostDetails(entry)
+	require.NoError(t, err)
+	require.NotNil(t, entry.HostDetails.Password)
+	assert.Equal(t, password, *entry.HostDetails.Password)
+
+	_, err = client.Entries.Host.Get("missing")
+	assert.ErrorIs(t, err, dvls.ErrResultNotFound)
+}
+
+func TestServer_Website(t *testing.T) {
+	server := NewServer(t)
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	password := "webpass"
+	website := server.AddWebsite(dvls.EntryWebsite{
+		VaultId:        vault.Id,
+		EntryName:      "website",
+		WebsiteDetails: dvls.EntryWebsiteAuthDetails{Username: "admin", URL: "https://example.com", Password: &password},
+	})
+	client := newClient(t, server)
+
+	entry, err := client.Entries.Website.Get(website.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", entry.WebsiteDetails.URL)
+	assert.Nil(t, entry.WebsiteDetails.Password)
+
+	entry, err = client.Entries.Website.GetWebsiteDetails(entry)
+	require.NoError(t, err)
+	require.NotNil(t, entry.WebsiteDetails.Password)
+	assert.Equal(t, password, *entry.WebsiteDetails.Password)
+}
+
+func TestServer_Certificate(t *testing.T) {
+	server := NewServer(t)
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	client := newClient(t, server)
+
+	content := []byte("-----BEGIN CERTIFICATE-----")
+	created, err := client.Entries.Certificate.NewFile(dvls.EntryCertificate{
+		VaultId:               vault.Id,
+		Name:                  "certificate",
+		Password:              "certpass",
+		CertificateIdentifier: "cert.pem",
+	}, content)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Id)
+
+	entry, err := client.Entries.Certificate.Get(created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "certificate", entry.Name)
+	assert.Equal(t, dvls.EntryCertificateDataModeFile, entry.GetDataMode())
+	assert.Empty(t, entry.Password)
+
+	entry, err = client.Entries.Certificate.GetPassword(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "certpass", entry.Password)
+
+	fileContent, err := client.Entries.Certificate.GetFileContent(created.Id)
+	require.NoError(t, err)
+	assert.Equal(t, content, fileContent)
+
+	entry.Description = "updated"
+	_, err = client.Entries.Certificate.Update(entry)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Entries.Certificate.Delete(created.Id))
+	_, err = client.Entries.Certificate.Get(created.Id)
+	assert.ErrorIs(t, err, dvls.ErrResultNotFound)
+
+	seeded := server.AddCertificate(dvls.EntryCertificate{VaultId: vault.Id, Name: "url", CertificateIdentifier: "https://example.com/cert.pem"}, nil)
+	assert.Equal(t, dvls.EntryCertificateDataModeURL, seeded.GetDataMode())
+}
+
+func TestServer_Attachments(t *testing.T) {
+	server := NewServer(t)
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+	entry := server.AddCertificate(dvls.EntryCertificate{VaultId: vault.Id, Name: "certificate", CertificateIdentifier: "cert.pem"}, nil)
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	body, err := json.Marshal(dvls.EntryAttachment{EntryId: entry.Id, FileName: "cert.pem"})
+	require.NoError(t, err)
+	resp, err := client.RequestWithContext(ctx, server.URL+"/api/attachment/save", http.MethodPost, bytes.NewReader(body))
+	require.NoError(t, err)
+	require.NoError(t, resp.CheckRespSaveResult())
+
+	var attachment dvls.EntryAttachment
+	require.NoError(t, json.Unmarshal(resp.Response, &attachment))
+	require.NotEmpty(t, attachment.Id)
+	assert.Equal(t, attachment.Id, attachment.IdString)
+	assert.Equal(t, entry.Id, attachment.EntryIdString)
+
+	content := []byte("-----BEGIN CERTIFICATE-----")
+	resp, err = client.RequestWithContext(ctx, server.URL+"/api/attachment/"+attachment.Id+"/document", http.MethodPost, bytes.NewReader(content))
+	require.NoError(t, err)
+	require.NoError(t, resp.CheckRespSaveResult())
+
+	fileContent, err := client.Entries.Certificate.GetFileContent(entry.Id)
+	require.NoError(t, err)
+	assert.Equal(t, content, fileContent)
+
+	body, err = json.Marshal(dvls.EntryAttachment{EntryId: "missing", FileName: "cert.pem"})
+	require.NoError(t, err)
+	resp, err = client.RequestWithContext(ctx, server.URL+"/api/attachment/save", http.MethodPost, bytes.NewReader(body))
+	require.NoError(t, err)
+	assert.ErrorIs(t, resp.CheckRespSaveResult(), dvls.ErrResultNotFound)
+
+	resp, err = client.RequestWithContext(ctx, server.URL+"/api/attachment/missing/document", http.MethodPost, bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.ErrorIs(t, resp.CheckRespSaveResult(), dvls.ErrResultNotFound)
+
+	resp, err = client.RequestWithContext(ctx, server.URL+"/api/attachment/save", http.MethodPost, strings.NewReader("{"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, resp.CheckRespSaveResult(), dvls.ErrResultInvalidData)
+}
+
+func TestServer_ServerInfo(t *testing.T) {
+	server := NewServer(t, WithVersion("2025.1.0.0"))
+	client := newClient(t, server)
+
+	info, err := client.GetPublicServerInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "2025.1.0.0", info.Version)
+	assert.Equal(t, "UTC", info.TimeZone)
+	assert.Equal(t, server.URL, info.AccessUri)
+
+	info, err = client.GetPrivateServerInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "2025.1.0.0", info.Version)
+	assert.Equal(t, 1, countRequests(server, http.MethodGet, "/api/private-instance-information"))
+
+	timezones, err := client.GetServerTimezones()
+	require.NoError(t, err)
+	require.Len(t, timezones, 1)
+	assert.Equal(t, "UTC", timezones[0].Id)
+	assert.Equal(t, "00:00:00", timezones[0].BaseUtcOffset)
+	assert.False(t, timezones[0].SupportsDaylightSavingTime)
+}
+
+func TestServer_FaultStatusCode(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+	server.InjectFault(Fault{Method: http.MethodGet, Path: "/api/v1/vault", StatusCode: http.StatusInternalServerError, Times: 1})
+
+	_, err := client.Vaults.List()
+	var reqErr *dvls.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, http.StatusInternalServerError, reqErr.StatusCode)
+
+	_, err = client.Vaults.List()
+	assert.NoError(t, err)
+}
+
+func TestServer_FaultRetried(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server, dvls.WithRetryPolicy(dvls.RetryPolicy{
+		MaxAttempts:          3,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+	server.InjectFault(Fault{Path: "/api/v1/vault/{vaultId}", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	vault := server.AddVault(dvls.Vault{Name: "vault"})
+
+	_, err := client.Vaults.Get(vault.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 3, countRequests(server, http.MethodGet, "/api/v1/vault/"+vault.Id))
+}
+
+func TestServer_FaultResult(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+	server.InjectFault(Fault{Path: "/api/connections/partial/{id}", Result: new(dvls.SaveResultAccessDenied)})
+
+	_, err := client.Entries.Host.Get("any")
+	assert.ErrorIs(t, err, dvls.ErrResultAccessDenied)
+
+	server.ClearFaults()
+	server.InjectFault(Fault{Path: "/api/connections/partial/{id}", Result: new(dvls.SaveResultError), Times: 1})
+	_, err = client.Entries.Host.Get("any")
+	assert.ErrorIs(t, err, dvls.ErrResultError)
+
+	_, err = client.Entries.Host.Get("any")
+	assert.ErrorIs(t, err, dvls.ErrResultNotFound)
+}
+
+// fatalTB records the failures of the helpers of a Server instead of failing the test.
+type fatalTB struct {
+	testing.TB
+	failure string
+}
+
+func (tb *fatalTB) Helper() {}
+
+func (tb *fatalTB) Fatalf(format string, args ...any) {
+	tb.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+func TestServer_AddEntryUnknownVault(t *testing.T) {
+	tb := &fatalTB{TB: t}
+	server := NewServer(tb)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.AddEntry(dvls.Entry{VaultId: "missing", Name: "entry", Type: "Credential", SubType: "Default"})
+	}()
+	<-done
+
+	assert.Equal(t, "dvlstest: vault missing does not exist", tb.failure)
+}
+
+func TestServer_FaultCloseConnection(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+	server.InjectFault(Fault{Path: "/api/v1/vault", CloseConnection: true})
+
+	_, err := client.Vaults.List()
+	var reqErr *dvls.RequestError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &reqErr) && reqErr.StatusCode != 0)
+}
+
+func TestServer_FaultDelay(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+	server.InjectFault(Fault{Path: "/api/v1/vault", Delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Vaults.ListWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestServer_ExpireSessions(t *testing.T) {
+	server := NewServer(t)
+	client := newClient(t, server)
+
+	server.ExpireSessions()
+
+	_, err := client.Vaults.List()
+	require.NoError(t, err)
+	assert.Equal(t, 2, countRequests(server, http.MethodPost, "/api/v1/login"))
+}
@@ -0,0 +1,171 @@
+package dvlstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/Devolutions/go-dvls"
+)
+
+// AddVault stores a vault and returns it. An Id is generated when vault has none, and unset fields get
+// the defaults of DVLS.
+func (s *Server) AddVault(vault dvls.Vault) dvls.Vault {
+	if vault.Id == "" {
+		vault.Id = newID()
+	}
+	vault = vaultDefaults(vault)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.vaults = append(s.vaults, vault)
+
+	return vault
+}
+
+// Vault returns the stored vault with the given Id.
+func (s *Server) Vault(vaultId string) (dvls.Vault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.vaultIndex(vaultId)
+	if i < 0 {
+		return dvls.Vault{}, false
+	}
+
+	return s.vaults[i], true
+}
+
+// vaultIndex returns the index of a vault in s.vaults, or -1. s.mu must be held.
+func (s *Server) vaultIndex(vaultId string) int {
+	return slices.IndexFunc(s.vaults, func(v dvls.Vault) bool { return v.Id == vaultId })
+}
+
+func vaultDefaults(vault dvls.Vault) dvls.Vault {
+	if vault.ContentType == "" {
+		vault.ContentType = dvls.VaultContentTypeEverything
+	}
+	if vault.SecurityLevel == "" {
+		vault.SecurityLevel = dvls.VaultSecurityLevelStandard
+	}
+	if vault.Visibility == "" {
+		vault.Visibility = dvls.VaultVisibilityDefault
+	}
+	if vault.Type == "" {
+		vault.Type = "Repository"
+	}
+
+	return vault
+}
+
+type vaultRequest struct {
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description"`
+	ContentType   dvls.VaultContentType   `json:"contentType"`
+	SecurityLevel dvls.VaultSecurityLevel `json:"securityLevel"`
+	Visibility    dvls.VaultVisibility    `json:"visibility"`
+}
+
+// decodeVaultRequest decodes and validates the body of a vault creation or update, writing an error
+// response when it is invalid.
+func decodeVaultRequest(w http.ResponseWriter, r *http.Request) (vaultRequest, bool) {
+	var req vaultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return vaultRequest{}, false
+	}
+	if req.Name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"message": "One or more validation errors occurred.",
+			"errors":  map[string][]string{"name": {"The name field is required."}},
+		})
+		return vaultRequest{}, false
+	}
+
+	return req, true
+}
+
+func (s *Server) handleListVaults(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	resp := page(s, r, s.vaults)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateVault(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeVaultRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.ContentType == dvls.VaultContentTypeDefault {
+		writeError(w, http.StatusBadRequest, "the Default content type is reserved to system vaults")
+		return
+	}
+
+	vault := s.AddVault(dvls.Vault{
+		Name:          req.Name,
+		Description:   req.Description,
+		ContentType:   req.ContentType,
+		SecurityLevel: req.SecurityLevel,
+		Visibility:    req.Visibility,
+	})
+
+	writeJSON(w, http.StatusCreated, vault)
+}
+
+func (s *Server) handleGetVault(w http.ResponseWriter, r *http.Request) {
+	vault, ok := s.Vault(r.PathValue("vaultId"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, vault)
+}
+
+func (s *Server) handleUpdateVault(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeVaultRequest(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	i := s.vaultIndex(r.PathValue("vaultId"))
+	if i < 0 {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+	vault := s.vaults[i]
+	vault.Name = req.Name
+	vault.Description = req.Description
+	vault.ContentType = req.ContentType
+	vault.SecurityLevel = req.SecurityLevel
+	vault.Visibility = req.Visibility
+	vault = vaultDefaults(vault)
+	s.vaults[i] = vault
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, vault)
+}
+
+func (s *Server) handleDeleteVault(w http.ResponseWriter, r *http.Request) {
+	vaultId := r.PathValue("vaultId")
+
+	s.mu.Lock()
+	i := s.vaultIndex(vaultId)
+	if i >= 0 {
+		s.vaults = slices.Delete(s.vaults, i, i+1)
+		s.entries = slices.DeleteFunc(s.entries, func(e *storedEntry) bool { return e.VaultId == vaultId })
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "vault not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
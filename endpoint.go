@@ -0,0 +1,116 @@
+package dvls
+
+import (
+	"net/url"
+	"strings"
+)
+
+// endpointTemplates lists the endpoints called by the client, with their path parameters in braces.
+var endpointTemplates = []string{
+	loginEndpoint,
+	logoutEndpoint,
+	userLoginEndpoint,
+	isLoggedEndpoint,
+	vaultEndpoint,
+	vaultEndpoint + "/{vaultId}",
+	entryBasePublicEndpoint,
+	entryPublicEndpoint,
+	entryEndpoint + "/save",
+	entryEndpoint + "/{id}",
+	entryEndpoint + "/{id}/sensitive-data",
+	entryConnectionsEndpoint + "/{id}/document",
+	attachmentEndpoint + "/save",
+	attachmentEndpoint + "/{id}/document",
+	"/" + serverPublicInfoEndpoint,
+	"/" + serverPrivateInfoEndpoint,
+	serverTimezonesEndpoint,
+}
+
+// endpointTemplate returns the templated endpoint of a request URL, such as /api/v1/vault/{vaultId}/entry/{id},
+// so that requests to the same endpoint can be grouped without exposing identifiers.
+// Unknown paths have their identifier-like segments replaced by {id}.
+func (c *Client) endpointTemplate(reqUrl string) string {
+	u, err := url.Parse(reqUrl)
+	if err != nil {
+		return ""
+	}
+
+	path := u.Path
+	if base, err := url.Parse(c.baseUri); err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+
+	return matchEndpointTemplate(path)
+}
+
+// matchEndpointTemplate returns the template matching path. Literal segments take precedence over parameters.
+func matchEndpointTemplate(path string) string {
+	segments := splitPath(path)
+
+	best, bestLiterals := "", -1
+	for _, template := range endpointTemplates {
+		literals, ok := matchTemplateSegments(splitPath(template), segments)
+		if ok && literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	for i, segment := range segments {
+		if isIdentifierSegment(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// matchTemplateSegments reports whether the path segments match the template segments and returns the
+// number of literal segments matched.
+func matchTemplateSegments(template []string, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+
+	literals := 0
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			continue
+		}
+		if !strings.EqualFold(part, segments[i]) {
+			return 0, false
+		}
+		literals++
+	}
+
+	return literals, true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// isIdentifierSegment reports whether a path segment looks like an identifier: a GUID or a number.
+func isIdentifierSegment(segment string) bool {
+	if len(segment) == 36 && segment[8] == '-' && segment[13] == '-' && segment[18] == '-' && segment[23] == '-' {
+		return true
+	}
+
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -61,6 +63,15 @@ type Entry struct {
 
 type EntryData any
 
+// LogValue implements the slog.LogValuer interface. The secrets of the entry data are redacted; they are
+// redacted by the fmt package as well, since the data types implement fmt.Formatter.
+func (e Entry) LogValue() slog.Value {
+	type plain Entry
+	r := plain(e)
+	r.Data = redactedValue(e.Data)
+	return slog.AnyValue(r)
+}
+
 func (e *Entry) GetType() string {
 	return e.Type
 }
@@ -165,11 +176,29 @@ func entryPublicBaseEndpointReplacer(vaultId string) string {
 
 // entryListRawResponse represents the raw paginated response from the entry list endpoint.
 type entryListRawResponse struct {
-	Data        []json.RawMessage `json:"data"`
-	CurrentPage int               `json:"currentPage"`
-	PageSize    int               `json:"pageSize"`
-	TotalCount  int               `json:"totalCount"`
-	TotalPage   int               `json:"totalPage"`
+	Data        []listedEntry `json:"data"`
+	CurrentPage int           `json:"currentPage"`
+	PageSize    int           `json:"pageSize"`
+	TotalCount  int           `json:"totalCount"`
+	TotalPage   int           `json:"totalPage"`
+}
+
+// listedEntry is an entry of the entry list endpoint. Entries of a type the client does not support are
+// flagged rather than failing the whole page.
+type listedEntry struct {
+	entry       Entry
+	unsupported bool
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *listedEntry) UnmarshalJSON(data []byte) error {
+	err := e.entry.UnmarshalJSON(data)
+	if IsUnsupportedEntryType(err) {
+		e.unsupported = true
+		return nil
+	}
+
+	return err
 }
 
 // GetByNameOptions contains optional filters for GetByName.
@@ -185,80 +214,160 @@ type GetEntriesOptions struct {
 	Path *string
 }
 
-// getEntries returns a list of entries from a vault with optional filters.
-// Entries with unsupported types are skipped.
-// This function handles pagination automatically and returns all entries across all pages.
-func (c *Client) getEntries(ctx context.Context, vaultId string, opts GetEntriesOptions) ([]Entry, error) {
+// getEntries returns a list of entries from a vault with optional filters and, unless entryType is empty,
+// of the given type. Entries with unsupported types are skipped.
+// This function handles pagination automatically and returns all entries across all pages. After the
+// first page, the remaining pages are fetched concurrently, see WithPageConcurrency.
+func (c *Client) getEntries(ctx context.Context, vaultId string, opts GetEntriesOptions, entryType string) ([]Entry, error) {
 	if vaultId == "" {
 		return nil, fmt.Errorf("vaultId is required")
 	}
 
+	return fetchAllPages(ctx, c.pageConcurrency, func(ctx context.Context, currentPage int) ([]Entry, int, error) {
+		page, err := c.getEntriesPage(ctx, vaultId, opts, PageOptions{Page: currentPage})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var entries []Entry
+		for _, entry := range page.Items {
+			if matchesEntryFilters(entry, opts, entryType) {
+				entries = append(entries, entry)
+			}
+		}
+
+		return entries, page.TotalPage, nil
+	})
+}
+
+// allEntries returns an iterator over the entries of a vault matching the optional filters and, unless
+// entryType is empty, of the given type. Entries with unsupported types are skipped.
+// Pages are fetched lazily and no further page is fetched once the caller stops iterating.
+func (c *Client) allEntries(ctx context.Context, vaultId string, opts GetEntriesOptions, entryType string) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		if vaultId == "" {
+			yield(Entry{}, fmt.Errorf("vaultId is required"))
+			return
+		}
+
+		for currentPage := 1; ; currentPage++ {
+			page, err := c.getEntriesPage(ctx, vaultId, opts, PageOptions{Page: currentPage})
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+
+			for _, entry := range page.Items {
+				if !matchesEntryFilters(entry, opts, entryType) {
+					continue
+				}
+				if !yield(entry, nil) {
+					return
+				}
+			}
+
+			// Check if we've fetched all pages
+			if currentPage >= page.TotalPage {
+				return
+			}
+		}
+	}
+}
+
+// listEntriesPage returns a single page of the entries of a vault, keeping only the entries matching the
+// optional filters and, unless entryType is empty, of the given type. The totals are the ones reported by
+// the server, which counts the entries of every type.
+func (c *Client) listEntriesPage(ctx context.Context, vaultId string, opts GetEntriesOptions, pageOpts PageOptions, entryType string) (Page[Entry], error) {
+	if vaultId == "" {
+		return Page[Entry]{}, fmt.Errorf("vaultId is required")
+	}
+	if err := pageOpts.validate(); err != nil {
+		return Page[Entry]{}, err
+	}
+
+	page, err := c.getEntriesPage(ctx, vaultId, opts, pageOpts)
+	if err != nil {
+		return Page[Entry]{}, err
+	}
+
+	entries := make([]Entry, 0, len(page.Items))
+	for _, entry := range page.Items {
+		if matchesEntryFilters(entry, opts, entryType) {
+			entries = append(entries, entry)
+		}
+	}
+	page.Items = entries
+
+	return page, nil
+}
+
+// getEntriesPage fetches a single page of entries, without applying the client-side filters.
+func (c *Client) getEntriesPage(ctx context.Context, vaultId string, opts GetEntriesOptions, pageOpts PageOptions) (Page[Entry], error) {
+	currentPage := pageOpts.page()
 	baseEndpoint := entryPublicBaseEndpointReplacer(vaultId)
 	reqUrl, err := url.JoinPath(c.baseUri, baseEndpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build entry url: %w", err)
+		return Page[Entry]{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
 	parsedUrl, err := url.Parse(reqUrl)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse entry url: %w", err)
+		return Page[Entry]{}, fmt.Errorf("failed to parse entry url: %w", err)
 	}
 
-	var allEntries []Entry
-	currentPage := 1
-
-	for {
-		q := parsedUrl.Query()
-		if opts.Name != nil {
-			q.Set("name", *opts.Name)
-		}
-		if opts.Path != nil && *opts.Path != "" {
-			q.Set("path", *opts.Path)
-		}
-		q.Set("page", fmt.Sprintf("%d", currentPage))
-		parsedUrl.RawQuery = q.Encode()
+	q := parsedUrl.Query()
+	if opts.Name != nil {
+		q.Set("name", *opts.Name)
+	}
+	if opts.Path != nil && *opts.Path != "" {
+		q.Set("path", *opts.Path)
+	}
+	pageOpts.setQuery(q)
+	parsedUrl.RawQuery = q.Encode()
 
-		resp, err := c.RequestWithContext(ctx, parsedUrl.String(), http.MethodGet, nil)
-		if err != nil {
-			return nil, fmt.Errorf("error while fetching entries (page %d): %w", currentPage, err)
-		}
+	var rawResp entryListRawResponse
+	_, err = c.RequestWithContext(ctx, parsedUrl.String(), http.MethodGet, nil, RequestOptions{into: &rawResp})
+	if err != nil {
+		return Page[Entry]{}, fmt.Errorf("error while fetching entries (page %d): %w", currentPage, err)
+	}
 
-		var rawResp entryListRawResponse
-		if err := json.Unmarshal(resp.Response, &rawResp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal entry list response (page %d): %w", currentPage, err)
+	entries := make([]Entry, 0, len(rawResp.Data))
+	for _, listed := range rawResp.Data {
+		if listed.unsupported {
+			continue
 		}
+		entry := listed.entry
+		entry.VaultId = vaultId
+		entries = append(entries, entry)
+	}
 
-		for _, raw := range rawResp.Data {
-			var entry Entry
-			if err := json.Unmarshal(raw, &entry); err != nil {
-				if IsUnsupportedEntryType(err) {
-					continue
-				}
-				return nil, fmt.Errorf("failed to unmarshal entry (page %d): %w", currentPage, err)
-			}
-			entry.VaultId = vaultId
-			allEntries = append(allEntries, entry)
-		}
+	return Page[Entry]{
+		Items:       entries,
+		CurrentPage: rawResp.CurrentPage,
+		PageSize:    rawResp.PageSize,
+		TotalCount:  rawResp.TotalCount,
+		TotalPage:   rawResp.TotalPage,
+	}, nil
+}
 
-		// Check if we've fetched all pages
-		if currentPage >= rawResp.TotalPage {
-			break
-		}
-		currentPage++
+// matchesEntryFilters reports whether an entry matches the client-side filters: its type, unless entryType
+// is empty, and the path filter of GetEntriesOptions.
+func matchesEntryFilters(entry Entry, opts GetEntriesOptions, entryType string) bool {
+	if entryType != "" && entry.GetType() != entryType {
+		return false
 	}
 
-	// The server path filter is not exact, so we always apply client-side filtering when path
-	// is set. We match entries at the exact path or any sub-path (prefix + backslash separator).
-	// When path is "", the server ignores the filter, so we also handle root-level filtering here.
-	if opts.Path != nil {
-		var filtered []Entry
-		for _, entry := range allEntries {
-			if entry.Path == *opts.Path || (*opts.Path != "" && strings.HasPrefix(entry.Path, *opts.Path+"\\")) {
-				filtered = append(filtered, entry)
-			}
-		}
-		return filtered, nil
+	return matchesPathFilter(entry, opts.Path)
+}
+
+// matchesPathFilter reports whether an entry matches the path filter of GetEntriesOptions.
+// The server path filter is not exact, so we always apply client-side filtering when path
+// is set. We match entries at the exact path or any sub-path (prefix + backslash separator).
+// When path is "", the server ignores the filter, so we also handle root-level filtering here.
+func matchesPathFilter(entry Entry, path *string) bool {
+	if path == nil {
+		return true
 	}
 
-	return allEntries, nil
+	return entry.Path == *path || (*path != "" && strings.HasPrefix(entry.Path, *path+"\\"))
 }
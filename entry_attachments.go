@@ -21,9 +21,11 @@ type EntryAttachment struct {
 	Title         string `json:"title"`
 }
 
+// rawEntryAttachment is decoded without the EntryAttachment.UnmarshalJSON method.
+type rawEntryAttachment EntryAttachment
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (e *EntryAttachment) UnmarshalJSON(d []byte) error {
-	type rawEntryAttachment EntryAttachment
 	raw := struct {
 		Data rawEntryAttachment `json:"data"`
 	}{}
@@ -56,19 +58,20 @@ func (c *Client) newAttachmentRequest(ctx context.Context, attachment EntryAttac
 		return "", fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(entryJson))
+	var respData legacyData
+	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(entryJson), RequestOptions{into: &respData})
 	if err != nil {
 		return "", fmt.Errorf("error while submitting entry attachment request: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return "", err
 	}
 
-	err = json.Unmarshal(resp.Response, &attachment)
-	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal response body: %w", err)
+	var created rawEntryAttachment
+	if err = respData.decode(&created); err != nil {
+		return "", err
 	}
 
-	return attachment.Id, nil
+	return created.Id, nil
 }
 
 func (c *Client) uploadAttachment(ctx context.Context, fileBytes []byte, attachmentId string) error {
@@ -77,9 +80,8 @@ func (c *Client) uploadAttachment(ctx context.Context, fileBytes []byte, attachm
 		return fmt.Errorf("failed to build attachment url: %w", err)
 	}
 
-	contentType := http.DetectContentType(fileBytes)
-
-	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(fileBytes), RequestOptions{ContentType: contentType})
+	// DVLS has always received attachment documents with the default content type.
+	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(fileBytes))
 	if err != nil {
 		return fmt.Errorf("error while uploading entry attachment: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
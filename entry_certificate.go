@@ -5,9 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 )
 
@@ -31,6 +31,28 @@ type EntryCertificate struct {
 	data entryCertificateData
 }
 
+// SecretPassword returns the password as a SecretString, which is redacted when printed or logged.
+func (e EntryCertificate) SecretPassword() SecretString {
+	return NewSecretString(e.Password)
+}
+
+// Format implements the fmt.Formatter interface. The password is redacted.
+func (e EntryCertificate) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, e)
+}
+
+// LogValue implements the slog.LogValuer interface. The password is redacted.
+func (e EntryCertificate) LogValue() slog.Value {
+	return slog.AnyValue(e.redacted())
+}
+
+func (e EntryCertificate) redacted() any {
+	type plain EntryCertificate
+	r := plain(e)
+	r.Password = redactString(r.Password)
+	return r
+}
+
 type rawEntryCertificate struct {
 	Id              string      `json:"id,omitempty"`
 	VaultId         string      `json:"repositoryId"`
@@ -96,49 +118,62 @@ func (e EntryCertificate) MarshalJSON() ([]byte, error) {
 	return entryJson, nil
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface.
-func (e *EntryCertificate) UnmarshalJSON(d []byte) error {
-	rawString := struct {
-		Data string
-	}{}
-	err := json.Unmarshal(d, &rawString)
-	if err != nil && !strings.Contains(err.Error(), "cannot unmarshal object into Go struct") {
-		return err
-	}
-
-	raw := struct {
-		Data rawEntryCertificate
-	}{}
+// certificateData is the data field of the certificate responses: the certificate itself, or a JSON
+// string holding it, as sent by the sensitive-data endpoint.
+type certificateData rawEntryCertificate
 
-	if rawString.Data != "" {
-		err = json.Unmarshal([]byte(rawString.Data), &raw.Data)
-		if err != nil {
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *certificateData) UnmarshalJSON(d []byte) error {
+	if len(d) > 0 && d[0] == '"' {
+		var s string
+		if err := json.Unmarshal(d, &s); err != nil {
 			return err
 		}
-	} else {
-		err = json.Unmarshal(d, &raw)
-		if err != nil {
-			return err
+		if s == "" {
+			return nil
 		}
+		d = []byte(s)
 	}
 
-	e.Id = raw.Data.Id
-	e.VaultId = raw.Data.VaultId
-	e.Name = raw.Data.Name
-	e.Description = raw.Data.Description
-	e.EntryFolderPath = raw.Data.EntryFolderPath
-	e.Tags = keywordsToSlice(raw.Data.Tags)
-	e.Expiration = raw.Data.Expiration
+	return json.Unmarshal(d, (*rawEntryCertificate)(c))
+}
 
-	e.data.Mode = raw.Data.Data.Mode
-	e.CertificateIdentifier = raw.Data.Data.FileName
-	e.UseDefaultCredentials = raw.Data.Data.UseDefaultCredentials
-	e.Password = raw.Data.Data.Password.SensitiveData
-	e.data.FileSize = raw.Data.Data.FileSize
+// certificateResponse is the response of the certificate endpoints.
+type certificateResponse struct {
+	legacyResult
+	Data certificateData
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *EntryCertificate) UnmarshalJSON(d []byte) error {
+	var raw certificateResponse
+	err := json.Unmarshal(d, &raw)
+	if err != nil {
+		return err
+	}
+
+	e.setRaw(raw.Data)
 
 	return nil
 }
 
+// setRaw sets the fields of e decoded from a certificate response.
+func (e *EntryCertificate) setRaw(raw certificateData) {
+	e.Id = raw.Id
+	e.VaultId = raw.VaultId
+	e.Name = raw.Name
+	e.Description = raw.Description
+	e.EntryFolderPath = raw.EntryFolderPath
+	e.Tags = keywordsToSlice(raw.Tags)
+	e.Expiration = raw.Expiration
+
+	e.data.Mode = raw.Data.Mode
+	e.CertificateIdentifier = raw.Data.FileName
+	e.UseDefaultCredentials = raw.Data.UseDefaultCredentials
+	e.Password = raw.Data.Password.SensitiveData
+	e.data.FileSize = raw.Data.FileSize
+}
+
 // Get returns a single Certificate specified by entryId.
 func (c *EntryCertificateService) Get(entryId string) (EntryCertificate, error) {
 	return c.GetWithContext(context.Background(), entryId)
@@ -153,18 +188,21 @@ func (c *EntryCertificateService) GetWithContext(ctx context.Context, entryId st
 		return EntryCertificate{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	var respData legacyData
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryCertificate{}, fmt.Errorf("error while fetching entry: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryCertificate{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &entry)
-	if err != nil {
-		return EntryCertificate{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw certificateData
+	if err = respData.decode(&raw); err != nil {
+		return EntryCertificate{}, err
 	}
 
+	entry.setRaw(raw)
+
 	return entry, nil
 }
 
@@ -197,25 +235,25 @@ func (c *EntryCertificateService) GetPassword(entry EntryCertificate) (EntryCert
 // GetPasswordWithContext returns the password of the entry specified by entry.
 // The provided context can be used to cancel the request.
 func (c *EntryCertificateService) GetPasswordWithContext(ctx context.Context, entry EntryCertificate) (EntryCertificate, error) {
-	var entryPassword EntryCertificate
+	var respData legacyData
 	reqUrl, err := url.JoinPath(c.client.baseUri, entryEndpoint, entry.Id, "/sensitive-data")
 	if err != nil {
 		return EntryCertificate{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil)
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryCertificate{}, fmt.Errorf("error while fetching sensitive data: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryCertificate{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &entryPassword)
-	if err != nil {
-		return EntryCertificate{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw certificateData
+	if err = respData.decode(&raw); err != nil {
+		return EntryCertificate{}, err
 	}
 
-	entry.Password = entryPassword.Password
+	entry.Password = raw.Data.Password.SensitiveData
 
 	return entry, nil
 }
@@ -260,18 +298,21 @@ func (c *EntryCertificateService) newWithContext(ctx context.Context, entry Entr
 		return EntryCertificate{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(entryJson))
+	var respData legacyData
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(entryJson), RequestOptions{into: &respData})
 	if err != nil {
 		return EntryCertificate{}, fmt.Errorf("error while creating entry: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryCertificate{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &entry)
-	if err != nil {
-		return EntryCertificate{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw certificateData
+	if err = respData.decode(&raw); err != nil {
+		return EntryCertificate{}, err
 	}
 
+	entry.setRaw(raw)
+
 	if content != nil {
 		attachment := EntryAttachment{
 			EntryId:   entry.Id,
@@ -320,18 +361,21 @@ func (c *EntryCertificateService) UpdateWithContext(ctx context.Context, entry E
 		return EntryCertificate{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPut, bytes.NewBuffer(entryJson))
+	var respData legacyData
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPut, bytes.NewBuffer(entryJson), RequestOptions{into: &respData})
 	if err != nil {
 		return EntryCertificate{}, fmt.Errorf("error while creating entry: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryCertificate{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &entry)
-	if err != nil {
-		return EntryCertificate{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw certificateData
+	if err = respData.decode(&raw); err != nil {
+		return EntryCertificate{}, err
 	}
 
+	entry.setRaw(raw)
+
 	return entry, nil
 }
 
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
 	"net/http"
 	"net/url"
 )
@@ -33,28 +35,138 @@ type EntryCredentialAccessCodeData struct {
 	Password string `json:"password,omitempty"`
 }
 
+// SecretPassword returns the password as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialAccessCodeData) SecretPassword() SecretString {
+	return NewSecretString(d.Password)
+}
+
+// Format implements the fmt.Formatter interface. The password is redacted.
+func (d EntryCredentialAccessCodeData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The password is redacted.
+func (d EntryCredentialAccessCodeData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialAccessCodeData) redacted() any {
+	type plain EntryCredentialAccessCodeData
+	r := plain(d)
+	r.Password = redactString(r.Password)
+	return r
+}
+
 type EntryCredentialApiKeyData struct {
 	ApiId    string `json:"apiId,omitempty"`
 	ApiKey   string `json:"apiKey,omitempty"`
 	TenantId string `json:"tenantId,omitempty"`
 }
 
+// SecretApiKey returns the API key as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialApiKeyData) SecretApiKey() SecretString {
+	return NewSecretString(d.ApiKey)
+}
+
+// Format implements the fmt.Formatter interface. The API key is redacted.
+func (d EntryCredentialApiKeyData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The API key is redacted.
+func (d EntryCredentialApiKeyData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialApiKeyData) redacted() any {
+	type plain EntryCredentialApiKeyData
+	r := plain(d)
+	r.ApiKey = redactString(r.ApiKey)
+	return r
+}
+
 type EntryCredentialAzureServicePrincipalData struct {
 	ClientId     string `json:"clientId,omitempty"`
 	ClientSecret string `json:"clientSecret,omitempty"`
 	TenantId     string `json:"tenantId,omitempty"`
 }
 
+// SecretClientSecret returns the client secret as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialAzureServicePrincipalData) SecretClientSecret() SecretString {
+	return NewSecretString(d.ClientSecret)
+}
+
+// Format implements the fmt.Formatter interface. The client secret is redacted.
+func (d EntryCredentialAzureServicePrincipalData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The client secret is redacted.
+func (d EntryCredentialAzureServicePrincipalData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialAzureServicePrincipalData) redacted() any {
+	type plain EntryCredentialAzureServicePrincipalData
+	r := plain(d)
+	r.ClientSecret = redactString(r.ClientSecret)
+	return r
+}
+
 type EntryCredentialConnectionStringData struct {
 	ConnectionString string `json:"connectionString,omitempty"`
 }
 
+// SecretConnectionString returns the connection string as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialConnectionStringData) SecretConnectionString() SecretString {
+	return NewSecretString(d.ConnectionString)
+}
+
+// Format implements the fmt.Formatter interface. The connection string is redacted.
+func (d EntryCredentialConnectionStringData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The connection string is redacted.
+func (d EntryCredentialConnectionStringData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialConnectionStringData) redacted() any {
+	type plain EntryCredentialConnectionStringData
+	r := plain(d)
+	r.ConnectionString = redactString(r.ConnectionString)
+	return r
+}
+
 type EntryCredentialDefaultData struct {
 	Domain   string `json:"domain,omitempty"`
 	Password string `json:"password,omitempty"`
 	Username string `json:"username,omitempty"`
 }
 
+// SecretPassword returns the password as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialDefaultData) SecretPassword() SecretString {
+	return NewSecretString(d.Password)
+}
+
+// Format implements the fmt.Formatter interface. The password is redacted.
+func (d EntryCredentialDefaultData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The password is redacted.
+func (d EntryCredentialDefaultData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialDefaultData) redacted() any {
+	type plain EntryCredentialDefaultData
+	r := plain(d)
+	r.Password = redactString(r.Password)
+	return r
+}
+
 type EntryCredentialPrivateKeyData struct {
 	Username   string `json:"privateKeyOverrideUsername,omitempty"`
 	Password   string `json:"privateKeyOverridePassword,omitempty"`
@@ -63,6 +175,35 @@ type EntryCredentialPrivateKeyData struct {
 	Passphrase string `json:"privateKeyPassPhrase,omitempty"`
 }
 
+// SecretPassword returns the override password as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialPrivateKeyData) SecretPassword() SecretString {
+	return NewSecretString(d.Password)
+}
+
+// SecretPassphrase returns the private key passphrase as a SecretString, which is redacted when printed or logged.
+func (d EntryCredentialPrivateKeyData) SecretPassphrase() SecretString {
+	return NewSecretString(d.Passphrase)
+}
+
+// Format implements the fmt.Formatter interface. The password, private key and passphrase are redacted.
+func (d EntryCredentialPrivateKeyData) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, d)
+}
+
+// LogValue implements the slog.LogValuer interface. The password, private key and passphrase are redacted.
+func (d EntryCredentialPrivateKeyData) LogValue() slog.Value {
+	return slog.AnyValue(d.redacted())
+}
+
+func (d EntryCredentialPrivateKeyData) redacted() any {
+	type plain EntryCredentialPrivateKeyData
+	r := plain(d)
+	r.Password = redactString(r.Password)
+	r.PrivateKey = redactString(r.PrivateKey)
+	r.Passphrase = redactString(r.Passphrase)
+	return r
+}
+
 func (e *Entry) GetCredentialAccessCodeData() (*EntryCredentialAccessCodeData, bool) {
 	if e == nil {
 		return nil, false
@@ -289,16 +430,11 @@ func (c *EntryCredentialService) GetByIdWithContext(ctx context.Context, vaultId
 		return Entry{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &entry})
 	if err != nil {
 		return Entry{}, fmt.Errorf("error while fetching entry: %w", err)
 	}
 
-	err = entry.UnmarshalJSON(resp.Response)
-	if err != nil {
-		return Entry{}, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
-
 	entry.VaultId = vaultId
 
 	return entry, nil
@@ -345,18 +481,13 @@ func (c *EntryCredentialService) NewWithContext(ctx context.Context, entry Entry
 		return "", fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(body))
-	if err != nil {
-		return "", fmt.Errorf("error while creating entry: %w", err)
-	}
-
 	newEntryResponse := struct {
 		Id string `json:"id"`
 	}{}
 
-	err = json.Unmarshal(resp.Response, &newEntryResponse)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(body), RequestOptions{into: &newEntryResponse})
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal response body: %w", err)
+		return "", fmt.Errorf("error while creating entry: %w", err)
 	}
 	return newEntryResponse.Id, nil
 }
@@ -462,23 +593,23 @@ func (c *EntryCredentialService) GetEntries(vaultId string, opts GetEntriesOptio
 // The provided context can be used to cancel the request.
 // Note: The API does not support filtering by entry type, so all entries are fetched and filtered client-side.
 func (c *EntryCredentialService) GetEntriesWithContext(ctx context.Context, vaultId string, opts GetEntriesOptions) ([]Entry, error) {
-	entries, err := c.client.getEntries(ctx, vaultId, GetEntriesOptions{
-		Name: opts.Name,
-		Path: opts.Path,
-	})
-	if err != nil {
-		return nil, err
-	}
+	return c.client.getEntries(ctx, vaultId, opts, EntryCredentialType)
+}
 
-	// Filter only Credential type entries
-	var credentials []Entry
-	for _, entry := range entries {
-		if entry.GetType() == EntryCredentialType {
-			credentials = append(credentials, entry)
-		}
-	}
+// All returns an iterator over the credential entries of a vault matching the optional filters. Pages are
+// fetched lazily as the iteration progresses, and no further page is fetched once the caller stops
+// iterating. The iteration stops after the first error. The provided context can be used to cancel the requests.
+// Note: The API does not support filtering by entry type, so the entries are filtered client-side.
+func (c *EntryCredentialService) All(ctx context.Context, vaultId string, opts GetEntriesOptions) iter.Seq2[Entry, error] {
+	return c.client.allEntries(ctx, vaultId, opts, EntryCredentialType)
+}
 
-	return credentials, nil
+// ListPage returns a single page of the credential entries of a vault matching the optional filters.
+// The provided context can be used to cancel the request.
+// Note: The API does not support filtering by entry type, so the entries of the page are filtered
+// client-side. A page may therefore hold fewer than PageSize items, and the totals count the entries of every type.
+func (c *EntryCredentialService) ListPage(ctx context.Context, vaultId string, opts GetEntriesOptions, page PageOptions) (Page[Entry], error) {
+	return c.client.listEntriesPage(ctx, vaultId, opts, page, EntryCredentialType)
 }
 
 // GetByName retrieves a single credential entry by name, subType, and optional filters.
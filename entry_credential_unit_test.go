@@ -1,6 +1,7 @@
 package dvls
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -98,6 +99,82 @@ func TestCredentialGetEntries_RootPathFilter(t *testing.T) {
 	assert.Equal(t, "InRoot", entries[0].Name)
 }
 
+func TestCredentialAll_StopsFetching(t *testing.T) {
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/api/v1/vault/%s/entry", testVaultID), func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"data": [
+				{"id":"folder-%[1]s","name":"Folder%[1]s","type":"Folder","subType":"Folder","path":"test","data":{}},
+				{"id":"cred-%[1]s","name":"Cred%[1]s","type":"Credential","subType":"Default","path":"test","data":{"username":"u%[1]s"}}
+			],
+			"currentPage": %[1]s,
+			"totalPage": 3,
+			"totalCount": 6,
+			"pageSize": 2
+		}`, page)
+	})
+
+	client := newTestClient(t, mux)
+
+	var ids []string
+	for entry, err := range client.Entries.Credential.All(context.Background(), testVaultID, GetEntriesOptions{}) {
+		require.NoError(t, err)
+		ids = append(ids, entry.Id)
+		break
+	}
+
+	assert.Equal(t, []string{"cred-1"}, ids)
+	assert.Equal(t, []string{"1"}, pages)
+}
+
+func TestCredentialAll_EmptyVaultId(t *testing.T) {
+	client := newTestClient(t, http.NewServeMux())
+
+	var errs []error
+	for _, err := range client.Entries.Credential.All(context.Background(), "", GetEntriesOptions{}) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "vaultId is required")
+}
+
+func TestCredentialListPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/api/v1/vault/%s/entry", testVaultID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		assert.Empty(t, r.URL.Query().Get("pageSize"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"data": [
+				{"id":"1","name":"Cred1","type":"Credential","subType":"Default","path":"test","data":{"username":"u1"}},
+				{"id":"2","name":"Folder1","type":"Folder","subType":"Folder","path":"test","data":{}}
+			],
+			"currentPage": 1,
+			"totalPage": 4,
+			"totalCount": 7,
+			"pageSize": 2
+		}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	page, err := client.Entries.Credential.ListPage(context.Background(), testVaultID, GetEntriesOptions{}, PageOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Cred1", page.Items[0].Name)
+	assert.Equal(t, testVaultID, page.Items[0].VaultId)
+	assert.Equal(t, 1, page.CurrentPage)
+	assert.Equal(t, 2, page.PageSize)
+	assert.Equal(t, 7, page.TotalCount)
+	assert.Equal(t, 4, page.TotalPage)
+	assert.True(t, page.HasNext())
+}
+
 func TestCredentialGetByName(t *testing.T) {
 	entryID := "entry-found"
 	mux := http.NewServeMux()
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 )
@@ -103,16 +104,11 @@ func (c *EntryFolderService) GetByIdWithContext(ctx context.Context, vaultId str
 		return Entry{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &entry})
 	if err != nil {
 		return Entry{}, fmt.Errorf("error while fetching entry: %w", err)
 	}
 
-	err = entry.UnmarshalJSON(resp.Response)
-	if err != nil {
-		return Entry{}, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
-
 	entry.VaultId = vaultId
 
 	return entry, nil
@@ -159,18 +155,13 @@ func (c *EntryFolderService) NewWithContext(ctx context.Context, entry Entry) (s
 		return "", fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(body))
-	if err != nil {
-		return "", fmt.Errorf("error while creating entry: %w", err)
-	}
-
 	newEntryResponse := struct {
 		Id string `json:"id"`
 	}{}
 
-	err = json.Unmarshal(resp.Response, &newEntryResponse)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(body), RequestOptions{into: &newEntryResponse})
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal response body: %w", err)
+		return "", fmt.Errorf("error while creating entry: %w", err)
 	}
 	return newEntryResponse.Id, nil
 }
@@ -298,21 +289,21 @@ func (c *EntryFolderService) GetEntries(vaultId string, opts GetEntriesOptions)
 // The provided context can be used to cancel the request.
 // Note: The API does not support filtering by entry type, so all entries are fetched and filtered client-side.
 func (c *EntryFolderService) GetEntriesWithContext(ctx context.Context, vaultId string, opts GetEntriesOptions) ([]Entry, error) {
-	entries, err := c.client.getEntries(ctx, vaultId, GetEntriesOptions{
-		Name: opts.Name,
-		Path: opts.Path,
-	})
-	if err != nil {
-		return nil, err
-	}
+	return c.client.getEntries(ctx, vaultId, opts, EntryFolderType)
+}
 
-	// Filter only Folder type entries
-	var folders []Entry
-	for _, entry := range entries {
-		if entry.GetType() == EntryFolderType {
-			folders = append(folders, entry)
-		}
-	}
+// All returns an iterator over the folder entries of a vault matching the optional filters. Pages are
+// fetched lazily as the iteration progresses, and no further page is fetched once the caller stops
+// iterating. The iteration stops after the first error. The provided context can be used to cancel the requests.
+// Note: The API does not support filtering by entry type, so the entries are filtered client-side.
+func (c *EntryFolderService) All(ctx context.Context, vaultId string, opts GetEntriesOptions) iter.Seq2[Entry, error] {
+	return c.client.allEntries(ctx, vaultId, opts, EntryFolderType)
+}
 
-	return folders, nil
+// ListPage returns a single page of the folder entries of a vault matching the optional filters.
+// The provided context can be used to cancel the request.
+// Note: The API does not support filtering by entry type, so the entries of the page are filtered
+// client-side. A page may therefore hold fewer than PageSize items, and the totals count the entries of every type.
+func (c *EntryFolderService) ListPage(ctx context.Context, vaultId string, opts GetEntriesOptions, page PageOptions) (Page[Entry], error) {
+	return c.client.listEntriesPage(ctx, vaultId, opts, page, EntryFolderType)
 }
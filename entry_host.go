@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 )
@@ -25,6 +26,15 @@ type EntryHost struct {
 	HostDetails EntryHostAuthDetails `json:"data"`
 }
 
+// LogValue implements the slog.LogValuer interface. The password is redacted; it is redacted by the fmt
+// package as well, since EntryHostAuthDetails implements fmt.Formatter.
+func (e EntryHost) LogValue() slog.Value {
+	type plain EntryHost
+	r := plain(e)
+	r.HostDetails.Password = redactStringPointer(e.HostDetails.Password)
+	return slog.AnyValue(r)
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (e EntryHost) MarshalJSON() ([]byte, error) {
 	raw := struct {
@@ -123,6 +133,33 @@ type EntryHostAuthDetails struct {
 	Host     string
 }
 
+// SecretPassword returns the password as a SecretString, which is redacted when printed or logged.
+// It is empty when the password was not fetched.
+func (s EntryHostAuthDetails) SecretPassword() SecretString {
+	if s.Password == nil {
+		return SecretString{}
+	}
+
+	return NewSecretString(*s.Password)
+}
+
+// Format implements the fmt.Formatter interface. The password is redacted.
+func (s EntryHostAuthDetails) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s)
+}
+
+// LogValue implements the slog.LogValuer interface. The password is redacted.
+func (s EntryHostAuthDetails) LogValue() slog.Value {
+	return slog.AnyValue(s.redacted())
+}
+
+func (s EntryHostAuthDetails) redacted() any {
+	type plain EntryHostAuthDetails
+	r := plain(s)
+	r.Password = redactStringPointer(r.Password)
+	return r
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (s EntryHostAuthDetails) MarshalJSON() ([]byte, error) {
 	raw := struct {
@@ -169,6 +206,7 @@ func (c *EntryHostService) GetHostDetails(entry EntryHost) (EntryHost, error) {
 // The provided context can be used to cancel the request.
 func (c *EntryHostService) GetHostDetailsWithContext(ctx context.Context, entry EntryHost) (EntryHost, error) {
 	var respData struct {
+		legacyResult
 		Data string `json:"data"`
 	}
 
@@ -177,17 +215,13 @@ func (c *EntryHostService) GetHostDetailsWithContext(ctx context.Context, entry
 		return EntryHost{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil)
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryHost{}, fmt.Errorf("error while fetching sensitive data: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryHost{}, err
 	}
 
-	if err := json.Unmarshal(resp.Response, &respData); err != nil {
-		return EntryHost{}, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
-
 	var sensitiveDataResponse struct {
 		Data struct {
 			PasswordItem struct {
@@ -220,16 +254,14 @@ func (s *EntryHostService) Get(entryId string) (EntryHost, error) {
 // the returned Entry to fetch the password.
 // The provided context can be used to cancel the request.
 func (s *EntryHostService) GetWithContext(ctx context.Context, entryId string) (EntryHost, error) {
-	var respData struct {
-		Data EntryHost `json:"data"`
-	}
+	var respData legacyData
 
 	reqUrl, err := url.JoinPath(s.client.baseUri, entryEndpoint, entryId)
 	if err != nil {
 		return EntryHost{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := s.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	resp, err := s.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryHost{}, fmt.Errorf("error fetching entry: %w", err)
 	}
@@ -237,13 +269,11 @@ func (s *EntryHostService) GetWithContext(ctx context.Context, entryId string) (
 	if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryHost{}, err
 	}
-	if resp.Response == nil {
-		return EntryHost{}, fmt.Errorf("response body is nil for request to %s", reqUrl)
-	}
 
-	if err := json.Unmarshal(resp.Response, &respData); err != nil {
-		return EntryHost{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	var entry EntryHost
+	if err = respData.decode(&entry); err != nil {
+		return EntryHost{}, err
 	}
 
-	return respData.Data, nil
+	return entry, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 )
@@ -25,6 +26,15 @@ type EntryWebsite struct {
 	WebsiteDetails EntryWebsiteAuthDetails `json:"data"`
 }
 
+// LogValue implements the slog.LogValuer interface. The password is redacted; it is redacted by the fmt
+// package as well, since EntryWebsiteAuthDetails implements fmt.Formatter.
+func (e EntryWebsite) LogValue() slog.Value {
+	type plain EntryWebsite
+	r := plain(e)
+	r.WebsiteDetails.Password = redactStringPointer(e.WebsiteDetails.Password)
+	return slog.AnyValue(r)
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (e EntryWebsite) MarshalJSON() ([]byte, error) {
 	raw := struct {
@@ -124,6 +134,33 @@ type EntryWebsiteAuthDetails struct {
 	WebBrowserApplication int
 }
 
+// SecretPassword returns the password as a SecretString, which is redacted when printed or logged.
+// It is empty when the password was not fetched.
+func (s EntryWebsiteAuthDetails) SecretPassword() SecretString {
+	if s.Password == nil {
+		return SecretString{}
+	}
+
+	return NewSecretString(*s.Password)
+}
+
+// Format implements the fmt.Formatter interface. The password is redacted.
+func (s EntryWebsiteAuthDetails) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, s)
+}
+
+// LogValue implements the slog.LogValuer interface. The password is redacted.
+func (s EntryWebsiteAuthDetails) LogValue() slog.Value {
+	return slog.AnyValue(s.redacted())
+}
+
+func (s EntryWebsiteAuthDetails) redacted() any {
+	type plain EntryWebsiteAuthDetails
+	r := plain(s)
+	r.Password = redactStringPointer(r.Password)
+	return r
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (s EntryWebsiteAuthDetails) MarshalJSON() ([]byte, error) {
 	raw := struct {
@@ -173,6 +210,7 @@ func (c *EntryWebsiteService) GetWebsiteDetails(entry EntryWebsite) (EntryWebsit
 // The provided context can be used to cancel the request.
 func (c *EntryWebsiteService) GetWebsiteDetailsWithContext(ctx context.Context, entry EntryWebsite) (EntryWebsite, error) {
 	var respData struct {
+		legacyResult
 		Data string `json:"data"`
 	}
 
@@ -181,17 +219,13 @@ func (c *EntryWebsiteService) GetWebsiteDetailsWithContext(ctx context.Context,
 		return EntryWebsite{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil)
+	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryWebsite{}, fmt.Errorf("error while fetching sensitive data: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryWebsite{}, err
 	}
 
-	if err := json.Unmarshal(resp.Response, &respData); err != nil {
-		return EntryWebsite{}, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
-
 	var sensitiveDataResponse struct {
 		Data struct {
 			PasswordItem struct {
@@ -224,29 +258,25 @@ func (s *EntryWebsiteService) Get(entryId string) (EntryWebsite, error) {
 // the returned Entry to fetch the password.
 // The provided context can be used to cancel the request.
 func (s *EntryWebsiteService) GetWithContext(ctx context.Context, entryId string) (EntryWebsite, error) {
-	var respData struct {
-		Data EntryWebsite `json:"data"`
-	}
+	var respData legacyData
 
 	reqUrl, err := url.JoinPath(s.client.baseUri, entryEndpoint, entryId)
 	if err != nil {
 		return EntryWebsite{}, fmt.Errorf("failed to build entry url: %w", err)
 	}
 
-	resp, err := s.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	resp, err := s.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return EntryWebsite{}, fmt.Errorf("error fetching entry: %w", err)
 	}
 	if err = resp.CheckRespSaveResult(); err != nil {
 		return EntryWebsite{}, err
 	}
-	if resp.Response == nil {
-		return EntryWebsite{}, fmt.Errorf("response body is nil for request to %s", reqUrl)
-	}
 
-	if err := json.Unmarshal(resp.Response, &respData); err != nil {
-		return EntryWebsite{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	var entry EntryWebsite
+	if err = respData.decode(&entry); err != nil {
+		return EntryWebsite{}, err
 	}
 
-	return respData.Data, nil
+	return entry, nil
 }
@@ -0,0 +1,68 @@
+package dvls
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs every request sent to DVLS with the method, templated endpoint, status code, latency and
+// result code. Successful requests are logged at the debug level and failed ones at the warning level.
+// At the debug level, the request and response bodies are logged as well, with credentials and session
+// tokens redacted. Responses of the /sensitive-data endpoints are never logged.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.logger = logger
+	}
+}
+
+// logBodies reports whether the request and response bodies are logged.
+func (c *Client) logBodies(ctx context.Context) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// logAttempt logs the outcome of a request. reqBody is only set when bodies are logged.
+func (c *Client) logAttempt(ctx context.Context, reqMethod string, reqUrl string, reqBody []byte, statusCode int, duration time.Duration, resp Response, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+	message := "dvls request"
+	if err != nil {
+		level = slog.LevelWarn
+		message = "dvls request failed"
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	endpoint := c.endpointTemplate(reqUrl)
+	attrs := []slog.Attr{
+		slog.String("method", reqMethod),
+		slog.String("endpoint", endpoint),
+		slog.Int("status", statusCode),
+		slog.Duration("duration", duration),
+	}
+	if resp.hasResult {
+		attrs = append(attrs, slog.String("result", SaveResult(resp.Result).String()))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if c.logBodies(ctx) {
+		if len(reqBody) > 0 {
+			attrs = append(attrs, slog.String("request_body", string(redactBody(reqBody))))
+		}
+		if len(resp.Response) > 0 {
+			respBody := []byte(redacted)
+			if !isSensitiveEndpoint(endpoint) {
+				respBody = redactBody(resp.Response)
+			}
+			attrs = append(attrs, slog.String("response_body", string(respBody)))
+		}
+	}
+
+	c.logger.LogAttrs(ctx, level, message, attrs...)
+}
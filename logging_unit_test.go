@@ -0,0 +1,175 @@
+package dvls
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLogVaultID = "5f1d2c7e-3b7a-4c39-9d4e-6a8b0c1d2e3f"
+	testLogEntryID = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+)
+
+// decodeLogRecords returns the records written by a slog JSON handler.
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestWithLogger_LogsRequestsWithRedaction(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault/{vaultId}/entry/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"entry","data":{"username":"user","password":"hunter2"}}`))
+	})
+	mux.HandleFunc("/api/connections/partial/{id}/sensitive-data", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":1,"data":{"passwordItem":{"sensitiveData":"hunter2"}}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLogger(logger))
+	require.NoError(t, err)
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/"+testLogVaultID+"/entry/"+testLogEntryID, http.MethodPut, bytes.NewBufferString(`{"data":{"password":"new-secret"}}`))
+	require.NoError(t, err)
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/connections/partial/"+testLogEntryID+"/sensitive-data", http.MethodGet, nil)
+	require.NoError(t, err)
+
+	output := buf.String()
+	for _, secret := range []string{"test-secret", "mock-token-123", "hunter2", "new-secret", testLogVaultID} {
+		assert.NotContains(t, output, secret)
+	}
+
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "/api/v1/login", records[0]["endpoint"])
+	assert.Equal(t, redacted, records[0]["request_body"])
+	assert.Contains(t, records[0]["response_body"], redacted)
+
+	assert.Equal(t, "dvls request", records[1]["msg"])
+	assert.Equal(t, http.MethodPut, records[1]["method"])
+	assert.Equal(t, "/api/v1/vault/{vaultId}/entry/{id}", records[1]["endpoint"])
+	assert.Equal(t, float64(http.StatusOK), records[1]["status"])
+	assert.Contains(t, records[1], "duration")
+	assert.JSONEq(t, `{"data":{"password":"[REDACTED]"}}`, records[1]["request_body"].(string))
+	assert.JSONEq(t, `{"id":"entry","data":{"username":"user","password":"[REDACTED]"}}`, records[1]["response_body"].(string))
+
+	assert.Equal(t, "/api/connections/partial/{id}/sensitive-data", records[2]["endpoint"])
+	assert.Equal(t, "Success", records[2]["result"])
+	assert.Equal(t, redacted, records[2]["response_body"])
+}
+
+func TestWithLogger_FailuresAtWarnLevel(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault/{vaultId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLogger(logger))
+	require.NoError(t, err)
+
+	_, err = client.Vaults.GetWithContext(t.Context(), testLogVaultID)
+	require.Error(t, err)
+
+	records := decodeLogRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "dvls request failed", records[0]["msg"])
+	assert.Equal(t, "/api/v1/vault/{vaultId}", records[0]["endpoint"])
+	assert.Equal(t, float64(http.StatusNotFound), records[0]["status"])
+	assert.Contains(t, records[0], "error")
+	assert.NotContains(t, records[0], "response_body")
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	client := &Client{baseUri: "https://dvls.example.com/dvls/"}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://dvls.example.com/dvls/api/v1/login", "/api/v1/login"},
+		{"https://dvls.example.com/dvls/api/v1/vault", "/api/v1/vault"},
+		{"https://dvls.example.com/dvls/api/v1/vault/" + testLogVaultID + "?pageNumber=2", "/api/v1/vault/{vaultId}"},
+		{"https://dvls.example.com/dvls/api/v1/vault/" + testLogVaultID + "/entry/" + testLogEntryID, "/api/v1/vault/{vaultId}/entry/{id}"},
+		{"https://dvls.example.com/dvls/api/connections/partial/save", "/api/connections/partial/save"},
+		{"https://dvls.example.com/dvls/api/connections/partial/" + testLogEntryID, "/api/connections/partial/{id}"},
+		{"https://dvls.example.com/dvls/api/unknown/" + testLogEntryID + "/42", "/api/unknown/{id}/{id}"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, client.endpointTemplate(tt.url), tt.url)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "secret keys",
+			body: `{"name":"entry","Password":"p","data":{"apiKey":"k","username":"u"},"items":[{"tokenId":"t"}]}`,
+			want: `{"name":"entry","Password":"[REDACTED]","data":{"apiKey":"[REDACTED]","username":"u"},"items":[{"tokenId":"[REDACTED]"}]}`,
+		},
+		{
+			name: "nested json string",
+			body: `{"data":"{\"password\":\"p\",\"host\":\"h\"}"}`,
+			want: `{"data":"{\"host\":\"h\",\"password\":\"[REDACTED]\"}"}`,
+		},
+		{
+			name: "empty secret is kept",
+			body: `{"password":""}`,
+			want: `{"password":""}`,
+		},
+		{
+			name: "secret object keeps its structure",
+			body: `{"password":{"hasSensitiveData":true,"sensitiveData":"p"}}`,
+			want: `{"password":{"hasSensitiveData":true,"sensitiveData":"[REDACTED]"}}`,
+		},
+		{
+			name: "form body",
+			body: `AppKey=key&AppSecret=secret`,
+			want: redacted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(redactBody([]byte(tt.body)))
+			if tt.want == redacted {
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
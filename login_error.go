@@ -0,0 +1,51 @@
+package dvls
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LoginError is returned when DVLS rejects a login attempt. Result holds the reason reported by the server.
+type LoginError struct {
+	Result  ServerLoginResult
+	Message string
+}
+
+func (e *LoginError) Error() string {
+	return fmt.Sprintf("unexpected login result %d (%s) %s", e.Result, e.Result, e.Message)
+}
+
+// loginResult returns the ServerLoginResult carried by a LoginError in err's chain.
+func loginResult(err error) (ServerLoginResult, bool) {
+	var loginErr *LoginError
+	if errors.As(err, &loginErr) {
+		return loginErr.Result, true
+	}
+
+	return 0, false
+}
+
+// IsInvalidCredentials reports whether the login failed because the username, password or application
+// secret was rejected.
+func IsInvalidCredentials(err error) bool {
+	result, ok := loginResult(err)
+	return ok && (result == ServerLoginInvalidUserNamePassword || result == ServerLoginUserNotFound)
+}
+
+// IsLockedUser reports whether the login failed because the account is locked.
+func IsLockedUser(err error) bool {
+	result, ok := loginResult(err)
+	return ok && (result == ServerLoginLockedUser || result == ServerLoginTwoFactorUserLockedOut)
+}
+
+// IsDisabledUser reports whether the login failed because the account is disabled or not yet approved.
+func IsDisabledUser(err error) bool {
+	result, ok := loginResult(err)
+	return ok && (result == ServerLoginDisabledUser || result == ServerLoginNotApprovedUser)
+}
+
+// IsExpiredSubscription reports whether the login failed because the DVLS license has expired.
+func IsExpiredSubscription(err error) bool {
+	result, ok := loginResult(err)
+	return ok && result == ServerLoginExpiredSubscription
+}
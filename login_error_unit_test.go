@@ -0,0 +1,110 @@
+package dvls
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAppKeyLoginServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestAppKeyLogin_LoginError(t *testing.T) {
+	server := newAppKeyLoginServer(t, http.StatusOK, `{"result":11,"message":"User is locked"}`)
+
+	_, err := NewClient("test-key", "test-secret", server.URL)
+	var loginErr *LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, ServerLoginLockedUser, loginErr.Result)
+	assert.Equal(t, "User is locked", loginErr.Message)
+	assert.True(t, IsLockedUser(err))
+	assert.False(t, IsInvalidCredentials(err))
+}
+
+func TestAppKeyLogin_LoginErrorWithStatusCode(t *testing.T) {
+	server := newAppKeyLoginServer(t, http.StatusUnauthorized, `{"result":2,"message":"Invalid credentials"}`)
+
+	_, err := NewClient("test-key", "wrong-secret", server.URL)
+	assert.True(t, IsInvalidCredentials(err))
+}
+
+func TestAppKeyLogin_MissingToken(t *testing.T) {
+	server := newAppKeyLoginServer(t, http.StatusOK, `{"message":"Something went wrong"}`)
+
+	_, err := NewClient("test-key", "test-secret", server.URL)
+	var loginErr *LoginError
+	require.ErrorAs(t, err, &loginErr)
+	assert.Equal(t, ServerLoginError, loginErr.Result)
+}
+
+func TestAppKeyLogin_UnrelatedFailure(t *testing.T) {
+	server := newAppKeyLoginServer(t, http.StatusInternalServerError, `internal error`)
+
+	_, err := NewClient("test-key", "test-secret", server.URL)
+	require.Error(t, err)
+	var loginErr *LoginError
+	assert.False(t, errors.As(err, &loginErr))
+}
+
+func TestLoginErrorHelpers(t *testing.T) {
+	tests := []struct {
+		result ServerLoginResult
+		check  func(error) bool
+	}{
+		{ServerLoginInvalidUserNamePassword, IsInvalidCredentials},
+		{ServerLoginUserNotFound, IsInvalidCredentials},
+		{ServerLoginLockedUser, IsLockedUser},
+		{ServerLoginTwoFactorUserLockedOut, IsLockedUser},
+		{ServerLoginDisabledUser, IsDisabledUser},
+		{ServerLoginExpiredSubscription, IsExpiredSubscription},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.result.String(), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", &LoginError{Result: tt.result})
+			assert.True(t, tt.check(err))
+		})
+	}
+
+	assert.False(t, IsExpiredSubscription(&LoginError{Result: ServerLoginLockedUser}))
+	assert.False(t, IsLockedUser(fmt.Errorf("not a login error")))
+}
+
+func TestUserPasswordLogin_LoginErrorWithStatusCode(t *testing.T) {
+	for _, body := range []string{
+		`{"data":{"result":11,"message":"User is locked"}}`,
+		`{"result":11,"message":"User is locked"}`,
+	} {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/login/partial", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(body))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		_, err := NewClientWithAuthenticator(t.Context(), server.URL, UserPasswordAuthenticator{Username: "user", Password: "password"})
+		var loginErr *LoginError
+		require.ErrorAs(t, err, &loginErr, body)
+		assert.Equal(t, "User is locked", loginErr.Message)
+		assert.True(t, IsLockedUser(err))
+		assert.False(t, IsInvalidCredentials(err))
+	}
+}
@@ -0,0 +1,94 @@
+package dvls
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ io.Closer = (*Client)(nil)
+
+func TestLogout_InvalidatesToken(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	var logoutToken string
+	mux.HandleFunc("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		logoutToken = r.Header.Get("tokenId")
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Logout(t.Context()))
+	assert.Equal(t, "mock-token-123", logoutToken)
+	assert.Empty(t, client.tokens.current())
+	assert.Nil(t, client.authenticator)
+
+	_, err = client.Vaults.GetWithContext(t.Context(), "test")
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.ErrorIs(t, client.Close(), ErrClientClosed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+}
+
+func TestClose_WithoutLoginSendsNoRequest(t *testing.T) {
+	var logoutCount int32
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&logoutCount, 1)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&logoutCount))
+
+	_, err = client.PublicRequestWithContext(t.Context(), server.URL+"/api/v1/login", http.MethodPost, loginContentType, nil)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
+
+func TestLogout_ExpiredTokenIsNotAnError(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+
+	assert.NoError(t, client.Logout(t.Context()))
+}
+
+func TestLogout_ServerErrorStillClosesClient(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+
+	assert.Error(t, client.Logout(t.Context()))
+	assert.True(t, client.tokens.isClosed())
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault", http.MethodGet, nil)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
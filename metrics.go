@@ -0,0 +1,125 @@
+package dvls
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsRecorder receives measurements of the requests sent by a Client and of its logins.
+// Implementations must be safe for concurrent use. PrometheusMetrics is an implementation that
+// renders the Prometheus text exposition format.
+type MetricsRecorder interface {
+	// RecordRequest is called once for every request sent to DVLS, including retries and logins.
+	RecordRequest(metrics RequestMetrics)
+	// RecordLogin is called once for every login attempt.
+	RecordLogin(metrics LoginMetrics)
+}
+
+// RequestMetrics describes a request sent to DVLS.
+type RequestMetrics struct {
+	// Operation is the logical operation performed by the request, such as "vault.list", "entry.get" or "login".
+	Operation string
+	Method    string
+	// Endpoint is the templated endpoint of the request, such as /api/v1/vault/{vaultId}.
+	Endpoint string
+	// StatusCode is the HTTP status code of the response, or 0 when no response was received.
+	StatusCode int
+	// Result is the result code reported in the response body. It is only meaningful when HasResult is set.
+	Result SaveResult
+	// HasResult reports whether the response body carried a result code.
+	HasResult bool
+	Duration  time.Duration
+	Err       error
+}
+
+// Failed reports whether the request failed, either with an error or with a result code other than
+// SaveResultSuccess.
+func (m RequestMetrics) Failed() bool {
+	return m.Err != nil || (m.HasResult && m.Result != SaveResultSuccess)
+}
+
+// LoginMetrics describes a login to DVLS.
+type LoginMetrics struct {
+	// Relogin reports whether the login replaced a session token that expired or was rejected.
+	Relogin  bool
+	Duration time.Duration
+	Err      error
+}
+
+// WithMetrics reports measurements of the requests and logins of the client to recorder.
+func WithMetrics(recorder MetricsRecorder) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.metrics = recorder
+	}
+}
+
+// endpointOperations maps a method and templated endpoint to the logical operation they perform.
+var endpointOperations = map[string]string{
+	http.MethodPost + " " + loginEndpoint:                              "login",
+	http.MethodPost + " " + userLoginEndpoint:                          "login",
+	http.MethodPost + " " + logoutEndpoint:                             "logout",
+	http.MethodGet + " " + isLoggedEndpoint:                            "is_logged",
+	http.MethodGet + " " + vaultEndpoint:                               "vault.list",
+	http.MethodPost + " " + vaultEndpoint:                              "vault.create",
+	http.MethodGet + " " + vaultEndpoint + "/{vaultId}":                "vault.get",
+	http.MethodPut + " " + vaultEndpoint + "/{vaultId}":                "vault.update",
+	http.MethodDelete + " " + vaultEndpoint + "/{vaultId}":             "vault.delete",
+	http.MethodGet + " " + entryBasePublicEndpoint:                     "entry.list",
+	http.MethodPost + " " + entryBasePublicEndpoint:                    "entry.create",
+	http.MethodGet + " " + entryPublicEndpoint:                         "entry.get",
+	http.MethodPut + " " + entryPublicEndpoint:                         "entry.update",
+	http.MethodDelete + " " + entryPublicEndpoint:                      "entry.delete",
+	http.MethodGet + " " + entryEndpoint + "/{id}":                     "entry.get",
+	http.MethodDelete + " " + entryEndpoint + "/{id}":                  "entry.delete",
+	http.MethodPut + " " + entryEndpoint + "/save":                     "entry.save",
+	http.MethodPost + " " + entryEndpoint + "/save":                    "entry.save",
+	http.MethodPost + " " + entryEndpoint + "/{id}/sensitive-data":     "entry.get_sensitive_data",
+	http.MethodGet + " " + entryConnectionsEndpoint + "/{id}/document": "entry.get_document",
+	http.MethodPost + " " + attachmentEndpoint + "/save":               "attachment.save",
+	http.MethodPost + " " + attachmentEndpoint + "/{id}/document":      "attachment.upload_document",
+	http.MethodGet + " /" + serverPublicInfoEndpoint:                   "server.public_info",
+	http.MethodGet + " /" + serverPrivateInfoEndpoint:                  "server.private_info",
+	http.MethodGet + " " + serverTimezonesEndpoint:                     "server.timezones",
+}
+
+// endpointOperation returns the logical operation performed by a request to a templated endpoint.
+// Unknown endpoints are identified by their method and endpoint.
+func endpointOperation(method string, endpoint string) string {
+	if operation, ok := endpointOperations[method+" "+endpoint]; ok {
+		return operation
+	}
+
+	return method + " " + endpoint
+}
+
+// recordRequest reports a request to the metrics recorder, if any.
+func (c *Client) recordRequest(reqMethod string, reqUrl string, statusCode int, duration time.Duration, resp Response, err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	endpoint := c.endpointTemplate(reqUrl)
+	metrics := RequestMetrics{
+		Operation:  endpointOperation(reqMethod, endpoint),
+		Method:     reqMethod,
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+		Duration:   duration,
+		Err:        err,
+	}
+	// Login responses report a ServerLoginResult rather than a SaveResult.
+	if metrics.Operation != "login" {
+		metrics.Result, metrics.HasResult = SaveResult(resp.Result), resp.hasResult
+	}
+
+	c.metrics.RecordRequest(metrics)
+}
+
+// recordLogin reports a login to the metrics recorder, if any.
+func (c *Client) recordLogin(relogin bool, duration time.Duration, err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	c.metrics.RecordLogin(LoginMetrics{Relogin: relogin, Duration: duration, Err: err})
+}
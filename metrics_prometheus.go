@@ -0,0 +1,202 @@
+package dvls
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets used by
+// NewPrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics is a MetricsRecorder that aggregates measurements in memory and renders them in the
+// Prometheus text exposition format. It implements http.Handler, so it can be served as a metrics endpoint.
+//
+// The following metrics are exposed:
+//   - dvls_requests_total: requests sent, by operation and HTTP status code.
+//   - dvls_request_errors_total: failed requests, by operation, HTTP status code and result code.
+//   - dvls_request_duration_seconds: request latency histogram, by operation.
+//   - dvls_logins_total: logins, by outcome.
+//   - dvls_relogins_total: logins that replaced an expired or rejected session token.
+type PrometheusMetrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	requests  map[requestSeries]uint64
+	errors    map[errorSeries]uint64
+	latencies map[string]*histogram
+	logins    map[string]uint64
+	relogins  uint64
+}
+
+type requestSeries struct {
+	operation string
+	status    string
+}
+
+type errorSeries struct {
+	operation string
+	status    string
+	result    string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics. Latencies are bucketed with buckets, given in
+// seconds, or with DefaultLatencyBuckets when none are provided.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		requests:  make(map[requestSeries]uint64),
+		errors:    make(map[errorSeries]uint64),
+		latencies: make(map[string]*histogram),
+		logins:    make(map[string]uint64),
+	}
+}
+
+// RecordRequest implements the MetricsRecorder interface.
+func (m *PrometheusMetrics) RecordRequest(metrics RequestMetrics) {
+	status := "none"
+	if metrics.StatusCode != 0 {
+		status = strconv.Itoa(metrics.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestSeries{operation: metrics.Operation, status: status}]++
+
+	if metrics.Failed() {
+		result := "none"
+		if metrics.HasResult {
+			result = metrics.Result.String()
+		}
+		m.errors[errorSeries{operation: metrics.Operation, status: status, result: result}]++
+	}
+
+	h, ok := m.latencies[metrics.Operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[metrics.Operation] = h
+	}
+	seconds := metrics.Duration.Seconds()
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// RecordLogin implements the MetricsRecorder interface.
+func (m *PrometheusMetrics) RecordLogin(metrics LoginMetrics) {
+	outcome := "success"
+	if metrics.Err != nil {
+		outcome = "failure"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logins[outcome]++
+	if metrics.Relogin {
+		m.relogins++
+	}
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	bw := bufio.NewWriter(counter)
+
+	m.mu.Lock()
+	m.write(bw)
+	m.mu.Unlock()
+
+	err := bw.Flush()
+	return counter.n, err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+func (m *PrometheusMetrics) write(w *bufio.Writer) {
+	writeHeader(w, "dvls_requests_total", "counter", "Requests sent to DVLS.")
+	for _, series := range slices.SortedFunc(maps.Keys(m.requests), func(a, b requestSeries) int {
+		return cmp.Or(strings.Compare(a.operation, b.operation), strings.Compare(a.status, b.status))
+	}) {
+		fmt.Fprintf(w, "dvls_requests_total{operation=%s,status=%s} %d\n",
+			quoteLabel(series.operation), quoteLabel(series.status), m.requests[series])
+	}
+
+	writeHeader(w, "dvls_request_errors_total", "counter", "Requests to DVLS that failed or returned an unsuccessful result code.")
+	for _, series := range slices.SortedFunc(maps.Keys(m.errors), func(a, b errorSeries) int {
+		return cmp.Or(strings.Compare(a.operation, b.operation), strings.Compare(a.status, b.status), strings.Compare(a.result, b.result))
+	}) {
+		fmt.Fprintf(w, "dvls_request_errors_total{operation=%s,status=%s,result=%s} %d\n",
+			quoteLabel(series.operation), quoteLabel(series.status), quoteLabel(series.result), m.errors[series])
+	}
+
+	writeHeader(w, "dvls_request_duration_seconds", "histogram", "Latency of the requests sent to DVLS.")
+	for _, operation := range slices.Sorted(maps.Keys(m.latencies)) {
+		h := m.latencies[operation]
+		label := quoteLabel(operation)
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "dvls_request_duration_seconds_bucket{operation=%s,le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "dvls_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(w, "dvls_request_duration_seconds_sum{operation=%s} %s\n", label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "dvls_request_duration_seconds_count{operation=%s} %d\n", label, h.count)
+	}
+
+	writeHeader(w, "dvls_logins_total", "counter", "Logins to DVLS.")
+	for _, outcome := range slices.Sorted(maps.Keys(m.logins)) {
+		fmt.Fprintf(w, "dvls_logins_total{outcome=%s} %d\n", quoteLabel(outcome), m.logins[outcome])
+	}
+
+	writeHeader(w, "dvls_relogins_total", "counter", "Logins that replaced an expired or rejected session token.")
+	fmt.Fprintf(w, "dvls_relogins_total %d\n", m.relogins)
+}
+
+func writeHeader(w *bufio.Writer, name string, metricType string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// quoteLabel quotes a label value, escaping backslashes, double quotes and line feeds.
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,120 @@
+package dvls
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetrics_RecordsRequestsAndLogins(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("tokenId") != "mock-token-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":[],"totalCount":0,"currentPage":1,"totalPage":1}`))
+	})
+	mux.HandleFunc("/api/connections/partial/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":2,"message":"denied"}`))
+	})
+	mux.HandleFunc("/api/connections/partial/{id}/error", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":0,"message":"failed"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	metrics := NewPrometheusMetrics()
+	client, err := NewClient("test-key", "test-secret", server.URL, WithMetrics(metrics))
+	require.NoError(t, err)
+
+	client.tokens.set("revoked-token", time.Now())
+	_, err = client.Vaults.ListWithContext(t.Context())
+	require.NoError(t, err)
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/connections/partial/"+testLogEntryID, http.MethodGet, nil)
+	require.NoError(t, err)
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/connections/partial/"+testLogEntryID+"/error", http.MethodGet, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = metrics.WriteTo(&buf)
+	require.NoError(t, err)
+	output := buf.String()
+
+	for _, line := range []string{
+		`dvls_requests_total{operation="login",status="200"} 2`,
+		`dvls_requests_total{operation="vault.list",status="200"} 1`,
+		`dvls_requests_total{operation="vault.list",status="401"} 1`,
+		`dvls_requests_total{operation="entry.get",status="200"} 1`,
+		`dvls_request_errors_total{operation="vault.list",status="401",result="none"} 1`,
+		`dvls_request_errors_total{operation="entry.get",status="200",result="AccessDenied"} 1`,
+		`dvls_request_errors_total{operation="GET /api/connections/partial/{id}/error",status="200",result="Error"} 1`,
+		`dvls_request_duration_seconds_bucket{operation="vault.list",le="+Inf"} 2`,
+		`dvls_request_duration_seconds_count{operation="login"} 2`,
+		`dvls_logins_total{outcome="success"} 2`,
+		`dvls_relogins_total 1`,
+		`# TYPE dvls_request_duration_seconds histogram`,
+	} {
+		assert.Contains(t, output, line+"\n")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&loginCount))
+}
+
+func TestPrometheusMetrics_Histogram(t *testing.T) {
+	metrics := NewPrometheusMetrics(1, 0.1)
+	metrics.RecordRequest(RequestMetrics{Operation: "vault.get", StatusCode: http.StatusOK, Duration: 50 * time.Millisecond})
+	metrics.RecordRequest(RequestMetrics{Operation: "vault.get", StatusCode: http.StatusOK, Duration: 500 * time.Millisecond})
+	metrics.RecordRequest(RequestMetrics{Operation: "vault.get", StatusCode: http.StatusOK, Duration: 2 * time.Second})
+
+	var buf bytes.Buffer
+	_, err := metrics.WriteTo(&buf)
+	require.NoError(t, err)
+
+	expected := strings.Join([]string{
+		`dvls_request_duration_seconds_bucket{operation="vault.get",le="0.1"} 1`,
+		`dvls_request_duration_seconds_bucket{operation="vault.get",le="1"} 2`,
+		`dvls_request_duration_seconds_bucket{operation="vault.get",le="+Inf"} 3`,
+		`dvls_request_duration_seconds_sum{operation="vault.get"} 2.55`,
+		`dvls_request_duration_seconds_count{operation="vault.get"} 3`,
+	}, "\n")
+	assert.Contains(t, buf.String(), expected)
+}
+
+func TestPrometheusMetrics_ServeHTTP(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	metrics.RecordLogin(LoginMetrics{Err: assert.AnError})
+	metrics.RecordRequest(RequestMetrics{Operation: "GET /api/\"odd\"\n", Err: assert.AnError})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `dvls_logins_total{outcome="failure"} 1`)
+	assert.Contains(t, rec.Body.String(), `dvls_requests_total{operation="GET /api/\"odd\"\n",status="none"} 1`)
+	assert.Contains(t, rec.Body.String(), "dvls_relogins_total 0\n")
+}
+
+func TestEndpointOperation(t *testing.T) {
+	assert.Equal(t, "entry.get", endpointOperation(http.MethodGet, entryPublicEndpoint))
+	assert.Equal(t, "vault.delete", endpointOperation(http.MethodDelete, vaultEndpoint+"/{vaultId}"))
+	assert.Equal(t, "PATCH /api/v1/vault", endpointOperation(http.MethodPatch, vaultEndpoint))
+}
+
+func TestRequestMetrics_Failed(t *testing.T) {
+	assert.False(t, RequestMetrics{}.Failed())
+	assert.False(t, RequestMetrics{Result: SaveResultSuccess, HasResult: true}.Failed())
+	assert.True(t, RequestMetrics{Result: SaveResultError, HasResult: true}.Failed())
+	assert.True(t, RequestMetrics{Result: SaveResultAccessDenied, HasResult: true}.Failed())
+	assert.True(t, RequestMetrics{Err: errors.New("failed")}.Failed())
+}
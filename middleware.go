@@ -0,0 +1,189 @@
+package dvls
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Doer sends HTTP requests. *http.Client implements Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer used to send requests to DVLS, for example to add tracing spans, correlation
+// IDs or custom headers. Middleware runs for every attempt, including retries and logins.
+type Middleware func(next Doer) Doer
+
+// RequestInfo describes a request about to be sent to DVLS.
+type RequestInfo struct {
+	Method string
+	URL    string
+}
+
+// ResponseInfo describes the outcome of a request sent to DVLS.
+type ResponseInfo struct {
+	Method string
+	URL    string
+	// StatusCode is the HTTP status code of the response, or 0 when no response was received.
+	StatusCode int
+	Duration   time.Duration
+	// Response is the parsed response. Its body is only set when WithHookSecrets is used.
+	Response Response
+	Err      error
+}
+
+// Hooks are called around every request sent to DVLS, including retries and logins. Either function may be nil.
+// Hooks are called synchronously and must be safe for concurrent use.
+type Hooks struct {
+	BeforeRequest func(ctx context.Context, info RequestInfo)
+	AfterResponse func(ctx context.Context, info ResponseInfo)
+}
+
+// WithMiddleware wraps every request sent to DVLS with the given middleware. The first middleware is the
+// outermost one. Unless WithHookSecrets is used, middleware never sees the tokenId header nor the request
+// body, which may contain credentials; they are attached once the middleware chain has run. Middleware is
+// trusted code: it receives the response as sent by DVLS, body included, so that it can audit it. It must
+// leave a readable body in the response it returns, for example a copy of the body it read.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.middleware = append(cfg.middleware, middleware...)
+	}
+}
+
+// WithHooks registers hooks called around every request sent to DVLS. It can be used several times.
+func WithHooks(hooks Hooks) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.hooks = append(cfg.hooks, hooks)
+	}
+}
+
+// WithHookSecrets lets middleware and hooks observe secrets: the tokenId header and the request bodies seen
+// by middleware, and the body of the Response passed to AfterResponse. Only use it with trusted code.
+func WithHookSecrets() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.hookSecrets = true
+	}
+}
+
+// buildDoer returns the middleware chain around httpClient, or nil when no middleware is configured.
+func (cfg *clientConfig) buildDoer(httpClient *http.Client) Doer {
+	if len(cfg.middleware) == 0 {
+		return nil
+	}
+
+	var doer Doer = secretsDoer{next: httpClient}
+	for i := len(cfg.middleware) - 1; i >= 0; i-- {
+		doer = cfg.middleware[i](doer)
+	}
+
+	return doer
+}
+
+// requestSecretsKey is the context key of the requestSecrets held back from the middleware chain.
+type requestSecretsKey struct{}
+
+// requestSecrets holds the parts of a request and its response that are hidden from middleware.
+type requestSecrets struct {
+	token         string
+	body          io.ReadCloser
+	getBody       func() (io.ReadCloser, error)
+	contentLength int64
+}
+
+// secretsDoer is the innermost Doer of the middleware chain. It attaches the secrets held back from the
+// middleware to the request.
+type secretsDoer struct {
+	next Doer
+}
+
+func (d secretsDoer) Do(req *http.Request) (*http.Response, error) {
+	secrets, ok := req.Context().Value(requestSecretsKey{}).(*requestSecrets)
+	if !ok {
+		return d.next.Do(req)
+	}
+
+	req = req.Clone(req.Context())
+	if secrets.token != "" {
+		req.Header.Set("tokenId", secrets.token)
+	}
+	if secrets.body != nil {
+		req.Body = secrets.body
+		req.GetBody = secrets.getBody
+		req.ContentLength = secrets.contentLength
+		secrets.body = nil
+		if secrets.getBody != nil {
+			// Middleware sending the request again gets a fresh body.
+			if body, err := secrets.getBody(); err == nil {
+				secrets.body = body
+			}
+		}
+	}
+
+	return d.next.Do(req)
+}
+
+// do sends req through the middleware chain, authenticated with token.
+func (c *Client) do(req *http.Request, token string) (*http.Response, error) {
+	if c.doer == nil || c.hookSecrets {
+		if token != "" {
+			req.Header.Add("tokenId", token)
+		}
+		if c.doer == nil {
+			return c.client.Do(req)
+		}
+		return c.doer.Do(req)
+	}
+
+	secrets := &requestSecrets{token: token, contentLength: req.ContentLength}
+	if req.Body != nil && req.Body != http.NoBody {
+		secrets.body = req.Body
+		secrets.getBody = req.GetBody
+	}
+	req = req.WithContext(context.WithValue(req.Context(), requestSecretsKey{}, secrets))
+	req.Body = http.NoBody
+	req.GetBody = nil
+	req.ContentLength = 0
+
+	resp, err := c.doer.Do(req)
+	if secrets.body != nil {
+		secrets.body.Close()
+	}
+
+	return resp, err
+}
+
+// beforeRequest calls the BeforeRequest hooks.
+func (c *Client) beforeRequest(ctx context.Context, info RequestInfo) {
+	for _, hooks := range c.hooks {
+		if hooks.BeforeRequest != nil {
+			hooks.BeforeRequest(ctx, info)
+		}
+	}
+}
+
+// afterResponse calls the AfterResponse hooks, hiding the response body unless secrets are exposed.
+func (c *Client) afterResponse(ctx context.Context, info ResponseInfo) {
+	if len(c.hooks) == 0 {
+		return
+	}
+
+	if !c.hookSecrets {
+		info.Response.Response = nil
+	}
+
+	for _, hooks := range c.hooks {
+		if hooks.AfterResponse != nil {
+			hooks.AfterResponse(ctx, info)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_WrapsRequestsWithoutSecrets(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "mock-token-123", r.Header.Get("tokenId"))
+		assert.Equal(t, "abc", r.Header.Get("X-Correlation-Id"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name":"v"}`, string(body))
+		w.Write([]byte(`{"id":"created"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var seen []string
+	middleware := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			assert.Empty(t, req.Header.Get("tokenId"))
+			body, _ := io.ReadAll(req.Body)
+			assert.Empty(t, body)
+
+			req.Header.Set("X-Correlation-Id", "abc")
+			resp, err := next.Do(req)
+			if err != nil {
+				return resp, err
+			}
+			// Auditing middleware reads the response body and puts a copy back.
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.True(t, json.Valid(respBody))
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+			mu.Lock()
+			seen = append(seen, req.Method+" "+req.URL.Path)
+			mu.Unlock()
+			return resp, nil
+		})
+	}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithMiddleware(middleware))
+	require.NoError(t, err)
+
+	resp, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault", http.MethodPost, bytes.NewBufferString(`{"name":"v"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"created"}`, string(resp.Response))
+	assert.Equal(t, []string{"POST /api/v1/login", "POST /api/v1/vault"}, seen)
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	var order []string
+	named := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.Do(req)
+			})
+		}
+	}
+
+	_, err := NewClient("test-key", "test-secret", server.URL, WithMiddleware(named("outer"), named("inner")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+}
+
+func TestMiddleware_ReplacementResponse(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	cache := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/api/v1/vault/cached" {
+				return next.Do(req)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(bytes.NewBufferString(`{"id":"cached"}`))}, nil
+		})
+	}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithMiddleware(cache))
+	require.NoError(t, err)
+
+	resp, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/cached", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"cached"}`, string(resp.Response))
+}
+
+// countingBody counts the bytes read through it.
+type countingBody struct {
+	io.ReadCloser
+	n *int
+}
+
+func (b countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	*b.n += n
+	return n, err
+}
+
+func TestMiddleware_WrappedResponseBody(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault/wrapped", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"wrapped"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	read := 0
+	wrap := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.Do(req)
+			if err != nil {
+				return resp, err
+			}
+			resp.Body = countingBody{ReadCloser: resp.Body, n: &read}
+			return resp, nil
+		})
+	}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithMiddleware(wrap))
+	require.NoError(t, err)
+
+	read = 0
+	resp, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/wrapped", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"wrapped"}`, string(resp.Response))
+	assert.Equal(t, len(`{"id":"wrapped"}`), read)
+}
+
+func TestMiddleware_HookSecrets(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var tokens []string
+	var bodies []string
+	middleware := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			tokens = append(tokens, req.Header.Get("tokenId"))
+			resp, err := next.Do(req)
+			if err != nil {
+				return resp, err
+			}
+			body, _ := io.ReadAll(resp.Body)
+			bodies = append(bodies, string(body))
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		})
+	}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithMiddleware(middleware), WithHookSecrets())
+	require.NoError(t, err)
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "mock-token-123"}, tokens)
+	assert.Contains(t, bodies[0], "mock-token-123")
+	assert.Equal(t, `{"id":"test"}`, bodies[1])
+}
+
+func TestHooks_ReceiveRequestOutcome(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+	mux.HandleFunc("/api/v1/vault/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var before []RequestInfo
+	var after []ResponseInfo
+	hooks := Hooks{
+		BeforeRequest: func(ctx context.Context, info RequestInfo) { before = append(before, info) },
+		AfterResponse: func(ctx context.Context, info ResponseInfo) { after = append(after, info) },
+	}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithHooks(hooks))
+	require.NoError(t, err)
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+	require.NoError(t, err)
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/missing", http.MethodGet, nil)
+	require.Error(t, err)
+
+	require.Len(t, before, 3)
+	require.Len(t, after, 3)
+	assert.Equal(t, RequestInfo{Method: http.MethodPost, URL: server.URL + "/api/v1/login"}, before[0])
+	assert.Equal(t, RequestInfo{Method: http.MethodGet, URL: server.URL + "/api/v1/vault/test"}, before[1])
+
+	assert.Equal(t, http.StatusOK, after[0].StatusCode)
+	assert.Nil(t, after[0].Response.Response)
+	assert.Equal(t, uint8(ServerLoginSuccess), after[0].Response.Result)
+
+	assert.Equal(t, http.StatusOK, after[1].StatusCode)
+	assert.Nil(t, after[1].Response.Response)
+	assert.NoError(t, after[1].Err)
+	assert.Positive(t, after[1].Duration)
+
+	assert.Equal(t, http.StatusNotFound, after[2].StatusCode)
+	assert.True(t, IsNotFound(after[2].Err))
+}
+
+func TestHooks_HookSecretsExposeBody(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	var bodies []string
+	hooks := Hooks{AfterResponse: func(ctx context.Context, info ResponseInfo) {
+		bodies = append(bodies, string(info.Response.Response))
+	}}
+
+	_, err := NewClient("test-key", "test-secret", server.URL, WithHooks(hooks), WithHookSecrets())
+	require.NoError(t, err)
+	require.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0], "mock-token-123")
+}
@@ -19,9 +19,10 @@ func newTestClient(t *testing.T, mux *http.ServeMux) *Client {
 	t.Cleanup(server.Close)
 
 	client := &Client{
-		baseUri:    server.URL,
-		client:     server.Client(),
-		credential: credentials{token: "test-token"},
+		baseUri:       server.URL,
+		client:        server.Client(),
+		authenticator: AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"},
+		tokens:        tokenManager{token: "test-token"},
 	}
 	client.common.client = client
 	client.Entries = &Entries{
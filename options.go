@@ -0,0 +1,126 @@
+package dvls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient or NewClientWithContext.
+type ClientOption func(*clientConfig)
+
+// clientConfig holds the settings collected from the ClientOption values.
+type clientConfig struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    *time.Duration
+	rootCAs    *x509.CertPool
+	userAgent  string
+	lazyLogin  bool
+
+	checkServerVersion bool
+
+	tokenMaxAge *time.Duration
+	retryPolicy *RetryPolicy
+
+	rateLimit             float64
+	rateBurst             int
+	maxConcurrentRequests int
+	pageConcurrency       *int
+	maxResponseSize       int64
+
+	middleware  []Middleware
+	hooks       []Hooks
+	hookSecrets bool
+
+	logger  *slog.Logger
+	metrics MetricsRecorder
+}
+
+// WithHTTPClient sets the http.Client used to communicate with DVLS. The client is copied, so later
+// options such as WithTimeout or WithRootCAs never modify the provided value.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send requests, for example to configure a proxy.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.transport = transport
+	}
+}
+
+// WithTimeout sets the time limit for each HTTP request made by the client. A zero timeout means no timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.timeout = &timeout
+	}
+}
+
+// WithRootCAs sets the certificate authorities used to verify the DVLS server certificate instead of the
+// system pool. The transport in use must be an *http.Transport.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.rootCAs = pool
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.userAgent = userAgent
+	}
+}
+
+// WithLazyLogin defers the initial login until the first request is made instead of logging in
+// when the client is created.
+func WithLazyLogin() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.lazyLogin = true
+	}
+}
+
+// buildHTTPClient returns the http.Client described by the configuration.
+func (cfg *clientConfig) buildHTTPClient() (*http.Client, error) {
+	httpClient := &http.Client{}
+	if cfg.httpClient != nil {
+		clientCopy := *cfg.httpClient
+		httpClient = &clientCopy
+	}
+
+	if cfg.transport != nil {
+		httpClient.Transport = cfg.transport
+	}
+
+	if cfg.rootCAs != nil {
+		var transport *http.Transport
+		switch t := httpClient.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		default:
+			return nil, fmt.Errorf("root CAs require an *http.Transport, got %T", httpClient.Transport)
+		}
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		transport.TLSClientConfig.RootCAs = cfg.rootCAs
+		httpClient.Transport = transport
+	}
+
+	if cfg.timeout != nil {
+		if *cfg.timeout < 0 {
+			return nil, fmt.Errorf("timeout must not be negative")
+		}
+		httpClient.Timeout = *cfg.timeout
+	}
+
+	return httpClient, nil
+}
@@ -0,0 +1,121 @@
+package dvls
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoginMux(t *testing.T, loginCount *int32) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		if loginCount != nil {
+			atomic.AddInt32(loginCount, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":1,"tokenId":"mock-token-123"}`))
+	})
+	mux.HandleFunc("/api/is-logged", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("true"))
+	})
+
+	return mux
+}
+
+func TestNewClient_LazyLogin(t *testing.T) {
+	var loginCount int32
+	server := httptest.NewServer(newLoginMux(t, &loginCount))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&loginCount))
+	assert.Empty(t, client.tokens.token)
+}
+
+func TestNewClient_UserAgent(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	var userAgent string
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithUserAgent("my-agent/1.0"))
+	require.NoError(t, err)
+
+	_, err = client.Vaults.Get("test")
+	require.NoError(t, err)
+	assert.Equal(t, "my-agent/1.0", userAgent)
+}
+
+func TestNewClient_WithTransport(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	var called bool
+	transport := &contextCheckTransport{
+		base:      http.DefaultTransport,
+		checkFunc: func(context.Context) { called = true },
+	}
+
+	_, err := NewClient("test-key", "test-secret", server.URL, WithTransport(transport))
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestNewClient_WithHTTPClientIsNotModified(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+	client, err := NewClient("test-key", "test-secret", server.URL, WithHTTPClient(httpClient), WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), httpClient.Timeout)
+	assert.Equal(t, 5*time.Second, client.client.Timeout)
+}
+
+func TestNewClient_WithRootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	_, err := NewClient("test-key", "test-secret", server.URL)
+	require.Error(t, err, "expected the test certificate to be rejected without custom root CAs")
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithRootCAs(pool))
+	require.NoError(t, err)
+	assert.Equal(t, "mock-token-123", client.tokens.token)
+}
+
+func TestNewClient_WithRootCAsRequiresHTTPTransport(t *testing.T) {
+	transport := &contextCheckTransport{base: http.DefaultTransport, checkFunc: func(context.Context) {}}
+
+	_, err := NewClient("test-key", "test-secret", "http://localhost", WithTransport(transport), WithRootCAs(x509.NewCertPool()))
+	assert.ErrorContains(t, err, "invalid client options")
+}
+
+func TestNewClientWithContext_Cancelled(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewClientWithContext(ctx, "test-key", "test-secret", server.URL)
+	assert.ErrorIs(t, err, context.Canceled)
+}
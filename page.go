@@ -0,0 +1,128 @@
+package dvls
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// defaultPageConcurrency is the number of pages fetched at the same time by the list operations
+// returning every item, such as Vaults.List.
+const defaultPageConcurrency = 4
+
+// WithPageConcurrency sets the number of pages fetched at the same time by the list operations returning
+// every item, such as Vaults.List or EntryCredentialService.GetEntries. A value of 1 fetches the pages one
+// after another. Requests are still subject to WithMaxConcurrentRequests.
+func WithPageConcurrency(concurrency int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.pageConcurrency = &concurrency
+	}
+}
+
+// PageOptions selects a page of a list operation.
+type PageOptions struct {
+	// Page is the 1-based number of the page. Zero selects the first page.
+	Page int
+	// PageSize is the number of items per page. Zero uses the server default.
+	PageSize int
+}
+
+// Page is a single page of a list operation along with the pagination totals reported by DVLS.
+type Page[T any] struct {
+	Items       []T
+	CurrentPage int
+	PageSize    int
+	TotalCount  int
+	TotalPage   int
+}
+
+// HasNext reports whether there are pages after this one.
+func (p Page[T]) HasNext() bool {
+	return p.CurrentPage < p.TotalPage
+}
+
+// page returns the number of the page, defaulting to the first page.
+func (o PageOptions) page() int {
+	if o.Page == 0 {
+		return 1
+	}
+
+	return o.Page
+}
+
+func (o PageOptions) validate() error {
+	if o.Page < 0 {
+		return fmt.Errorf("invalid page %d", o.Page)
+	}
+	if o.PageSize < 0 {
+		return fmt.Errorf("invalid page size %d", o.PageSize)
+	}
+
+	return nil
+}
+
+// setQuery sets the page and pageSize query parameters.
+func (o PageOptions) setQuery(q url.Values) {
+	q.Set("page", strconv.Itoa(o.page()))
+	if o.PageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+}
+
+// fetchAllPages returns the items of every page. The first page is fetched to learn the number of pages,
+// then the remaining pages are fetched with up to concurrency requests at a time. Items are returned in
+// page order. The first error cancels the requests still in flight and is returned.
+func fetchAllPages[T any](ctx context.Context, concurrency int, fetch func(ctx context.Context, page int) ([]T, int, error)) ([]T, error) {
+	first, totalPage, err := fetch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := [][]T{first}
+	if totalPage > 1 {
+		pages = append(pages, make([][]T, totalPage-1)...)
+
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		next := make(chan int)
+		var wg sync.WaitGroup
+		for range max(1, min(concurrency, totalPage-1)) {
+			wg.Go(func() {
+				for page := range next {
+					items, _, err := fetch(ctx, page)
+					if err != nil {
+						// Only the first cause is kept, so errors caused by the cancellation are ignored.
+						cancel(err)
+						continue
+					}
+					pages[page-1] = items
+				}
+			})
+		}
+
+	feed:
+		for page := 2; page <= totalPage; page++ {
+			select {
+			case next <- page:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(next)
+		wg.Wait()
+
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []T
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+
+	return items, nil
+}
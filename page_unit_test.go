@@ -0,0 +1,111 @@
+package dvls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchAllPages_Order(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+
+	items, err := fetchAllPages(context.Background(), 3, func(ctx context.Context, page int) ([]int, int, error) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		// Later pages answer first, so that the order does not depend on the completion order.
+		time.Sleep(time.Duration(10-page) * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		return []int{page * 10, page*10 + 1}, 8, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 11, 20, 21, 30, 31, 40, 41, 50, 51, 60, 61, 70, 71, 80, 81}, items)
+	assert.LessOrEqual(t, maxInFlight, 3)
+}
+
+func TestFetchAllPages_ErrorCancels(t *testing.T) {
+	errPage := errors.New("page 2 failed")
+
+	start := time.Now()
+	_, err := fetchAllPages(context.Background(), 4, func(ctx context.Context, page int) ([]int, int, error) {
+		switch page {
+		case 1:
+			return []int{1}, 50, nil
+		case 2:
+			return nil, 0, errPage
+		default:
+			select {
+			case <-ctx.Done():
+				return nil, 0, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return []int{page}, 50, nil
+			}
+		}
+	})
+	assert.ErrorIs(t, err, errPage)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestFetchAllPages_FirstPageError(t *testing.T) {
+	var calls int
+	_, err := fetchAllPages(context.Background(), 4, func(ctx context.Context, page int) ([]int, int, error) {
+		calls++
+		return nil, 0, errors.New("unavailable")
+	})
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 1, calls)
+}
+
+func TestVaultsList_ConcurrentPages(t *testing.T) {
+	var mu sync.Mutex
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		mu.Lock()
+		pages = append(pages, page)
+		mu.Unlock()
+
+		json.NewEncoder(w).Encode(vaultListResponse{Data: []Vault{{Id: "vault-" + page}}, TotalPage: 6})
+	})
+
+	client := newTestClient(t, mux)
+	client.pageConcurrency = 3
+
+	vaults, err := client.Vaults.List()
+	require.NoError(t, err)
+	require.Len(t, vaults, 6)
+	for i, vault := range vaults {
+		assert.Equal(t, "vault-"+strconv.Itoa(i+1), vault.Id)
+	}
+	assert.Equal(t, "1", pages[0])
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4", "5", "6"}, pages)
+}
+
+func TestNewClient_InvalidPageConcurrency(t *testing.T) {
+	_, err := NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithPageConcurrency(0))
+	assert.ErrorContains(t, err, "invalid client options")
+
+	client, err := NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin())
+	require.NoError(t, err)
+	assert.Equal(t, defaultPageConcurrency, client.pageConcurrency)
+
+	client, err = NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithPageConcurrency(8))
+	require.NoError(t, err)
+	assert.Equal(t, 8, client.pageConcurrency)
+}
@@ -0,0 +1,178 @@
+package dvls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// supportedServerMajor is the DVLS major version supported by this version of go-dvls.
+const supportedServerMajor = 2026
+
+// ServerVersion is a parsed DVLS version such as 2026.1.2.0. DVLS versions have up to four numeric
+// components, the last one being the build number.
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Build int
+}
+
+// ParseServerVersion parses a DVLS version made of one to four dot-separated numbers, with an optional
+// "v" prefix. Missing components are zero.
+func ParseServerVersion(version string) (ServerVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) > 4 {
+		return ServerVersion{}, fmt.Errorf("invalid server version %q", version)
+	}
+
+	var numbers [4]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return ServerVersion{}, fmt.Errorf("invalid server version %q", version)
+		}
+		numbers[i] = n
+	}
+
+	return ServerVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Build: numbers[3]}, nil
+}
+
+// String returns the version in the major.minor.patch.build format used by DVLS.
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d.%d", v.Major, v.Minor, v.Patch, v.Build)
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal to or greater than other.
+func (v ServerVersion) Compare(other ServerVersion) int {
+	for _, d := range [...]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch, v.Build - other.Build} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Compatibility is the verdict on whether a DVLS version is supported by this version of go-dvls.
+type Compatibility int
+
+const (
+	// CompatibilityUnknown is returned for versions that cannot be parsed or that are newer than the
+	// versions this release was tested with.
+	CompatibilityUnknown Compatibility = iota
+	// CompatibilitySupported is returned for the DVLS versions supported by this release.
+	CompatibilitySupported
+	// CompatibilityUnsupported is returned for DVLS versions too old for this release.
+	CompatibilityUnsupported
+)
+
+func (c Compatibility) String() string {
+	switch c {
+	case CompatibilitySupported:
+		return "supported"
+	case CompatibilityUnsupported:
+		return "unsupported"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckCompatibility returns whether version is supported by this version of go-dvls, which requires DVLS 2026.x.
+func CheckCompatibility(version ServerVersion) Compatibility {
+	switch {
+	case version.Major < supportedServerMajor:
+		return CompatibilityUnsupported
+	case version.Major == supportedServerMajor:
+		return CompatibilitySupported
+	default:
+		return CompatibilityUnknown
+	}
+}
+
+// UnsupportedServerError is returned by NewClient when WithServerVersionCheck is used and the DVLS version
+// is not supported.
+type UnsupportedServerError struct {
+	Version string
+}
+
+func (e *UnsupportedServerError) Error() string {
+	return fmt.Sprintf("DVLS version %s is not supported, go-dvls requires DVLS %d.x", e.Version, supportedServerMajor)
+}
+
+// IsUnsupportedServer reports whether err is caused by an unsupported DVLS version.
+func IsUnsupportedServer(err error) bool {
+	var unsupported *UnsupportedServerError
+	return errors.As(err, &unsupported)
+}
+
+// WithServerVersionCheck makes NewClient probe the DVLS version before logging in, and fail with an
+// *UnsupportedServerError when the version is not supported. Versions that cannot be parsed or that are
+// newer than the supported ones are accepted.
+func WithServerVersionCheck() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.checkServerVersion = true
+	}
+}
+
+// ProbeResult holds the outcome of Probe.
+type ProbeResult struct {
+	// Reachable reports whether DVLS answered, even with an error.
+	Reachable bool
+	// Latency is the duration of the request.
+	Latency time.Duration
+	Server  Server
+	// Version is the parsed Server.Version, zero when it cannot be parsed.
+	Version       ServerVersion
+	Compatibility Compatibility
+}
+
+// Probe checks that DVLS is reachable and returns its version along with a compatibility verdict. It does
+// not require a session token, so it neither logs in nor renews the token.
+// The provided context can be used to cancel the request.
+func (c *Client) Probe(ctx context.Context) (ProbeResult, error) {
+	start := time.Now()
+	server, err := c.GetPublicServerInfoWithContext(ctx)
+	result := ProbeResult{Latency: time.Since(start)}
+	if err != nil {
+		var reqErr *RequestError
+		result.Reachable = !errors.As(err, &reqErr) || reqErr.StatusCode != 0
+		return result, err
+	}
+
+	result.Reachable = true
+	result.Server = server
+	if version, err := ParseServerVersion(server.Version); err == nil {
+		result.Version = version
+		result.Compatibility = CheckCompatibility(version)
+	}
+
+	return result, nil
+}
+
+// Ping returns an error when DVLS is not reachable or does not answer successfully. Like Probe, it does
+// not require a session token.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Probe(ctx)
+	return err
+}
+
+// checkServerVersion returns an *UnsupportedServerError when the DVLS version is not supported.
+func (c *Client) checkServerVersion(ctx context.Context) error {
+	probe, err := c.Probe(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check the server version: %w", err)
+	}
+
+	if probe.Compatibility == CompatibilityUnsupported {
+		return &UnsupportedServerError{Version: probe.Server.Version}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package dvls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProbeMux(t *testing.T, version string, loginCount *int32) *http.ServeMux {
+	t.Helper()
+
+	mux := newLoginMux(t, loginCount)
+	mux.HandleFunc("GET /api/public-instance-information", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("tokenId"))
+		fmt.Fprintf(w, `{"result":1,"data":{"serverName":"dvls","version":%q}}`, version)
+	})
+
+	return mux
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    ServerVersion
+		wantErr bool
+	}{
+		{version: "2026.1.2.3", want: ServerVersion{Major: 2026, Minor: 1, Patch: 2, Build: 3}},
+		{version: "v2025.3", want: ServerVersion{Major: 2025, Minor: 3}},
+		{version: "2026", want: ServerVersion{Major: 2026}},
+		{version: "", wantErr: true},
+		{version: "2026.1.beta", wantErr: true},
+		{version: "2026.1.0.0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseServerVersion(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServerVersion_Compare(t *testing.T) {
+	v := ServerVersion{Major: 2026, Minor: 1, Patch: 2}
+
+	assert.Equal(t, 0, v.Compare(v))
+	assert.Equal(t, -1, v.Compare(ServerVersion{Major: 2026, Minor: 2}))
+	assert.Equal(t, 1, v.Compare(ServerVersion{Major: 2025, Minor: 9, Patch: 9, Build: 9}))
+	assert.Equal(t, "2026.1.2.0", v.String())
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	assert.Equal(t, CompatibilityUnsupported, CheckCompatibility(ServerVersion{Major: 2025, Minor: 3}))
+	assert.Equal(t, CompatibilitySupported, CheckCompatibility(ServerVersion{Major: 2026, Minor: 1}))
+	assert.Equal(t, CompatibilityUnknown, CheckCompatibility(ServerVersion{Major: 2027}))
+}
+
+func TestProbe(t *testing.T) {
+	var loginCount int32
+	server := httptest.NewServer(newProbeMux(t, "2026.1.0.0", &loginCount))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+
+	probe, err := client.Probe(context.Background())
+	require.NoError(t, err)
+	assert.True(t, probe.Reachable)
+	assert.Equal(t, "2026.1.0.0", probe.Server.Version)
+	assert.Equal(t, ServerVersion{Major: 2026, Minor: 1}, probe.Version)
+	assert.Equal(t, CompatibilitySupported, probe.Compatibility)
+	assert.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&loginCount))
+}
+
+func TestProbe_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NewServeMux())
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+	server.Close()
+
+	probe, err := client.Probe(context.Background())
+	assert.Error(t, err)
+	assert.False(t, probe.Reachable)
+}
+
+func TestNewClient_WithServerVersionCheck(t *testing.T) {
+	var loginCount int32
+	server := httptest.NewServer(newProbeMux(t, "2025.3.1.0", &loginCount))
+	defer server.Close()
+
+	_, err := NewClient("test-key", "test-secret", server.URL, WithServerVersionCheck())
+	assert.True(t, IsUnsupportedServer(err))
+	assert.EqualError(t, err, "DVLS version 2025.3.1.0 is not supported, go-dvls requires DVLS 2026.x")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&loginCount))
+
+	supported := httptest.NewServer(newProbeMux(t, "2026.1.0.0", nil))
+	defer supported.Close()
+
+	_, err = NewClient("test-key", "test-secret", supported.URL, WithServerVersionCheck())
+	assert.NoError(t, err)
+}
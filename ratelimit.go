@@ -0,0 +1,146 @@
+package dvls
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the rate at which requests are sent to DVLS using a token bucket that allows
+// requestsPerSecond requests on average with bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.rateLimit = requestsPerSecond
+		cfg.rateBurst = burst
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of requests sent to DVLS at the same time.
+func WithMaxConcurrentRequests(maxRequests int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.maxConcurrentRequests = maxRequests
+	}
+}
+
+// requestLimiter enforces the rate and concurrency limits of a Client.
+type requestLimiter struct {
+	rate  *rateLimiter
+	slots chan struct{}
+}
+
+// buildRequestLimiter returns the requestLimiter described by the configuration, or nil when no limit is set.
+func (cfg *clientConfig) buildRequestLimiter() (*requestLimiter, error) {
+	if cfg.rateLimit == 0 && cfg.rateBurst == 0 && cfg.maxConcurrentRequests == 0 {
+		return nil, nil
+	}
+
+	var limiter requestLimiter
+	if cfg.rateLimit != 0 || cfg.rateBurst != 0 {
+		if cfg.rateLimit <= 0 || cfg.rateBurst < 1 {
+			return nil, fmt.Errorf("rate limit must be positive with a burst of at least 1")
+		}
+		limiter.rate = newRateLimiter(cfg.rateLimit, cfg.rateBurst, time.Now())
+	}
+
+	if cfg.maxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("maximum concurrent requests must not be negative")
+	}
+	if cfg.maxConcurrentRequests > 0 {
+		limiter.slots = make(chan struct{}, cfg.maxConcurrentRequests)
+	}
+
+	return &limiter, nil
+}
+
+// acquire waits until a request may be sent and returns a function that must be called once the request
+// has completed. It returns the context error if the context is done while waiting.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// rateLimiter is a token bucket. Tokens are added at a fixed rate up to the burst size and each
+// request consumes one.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int, now time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// reserve takes a token from the bucket and returns how long the caller must wait before using it.
+// The bucket may go negative, which queues callers in the order they reserved.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token reserved by a caller that stopped waiting.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// wait blocks until a token is available or the context is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	delay := l.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+
+	if err := sleepWithContext(ctx, delay); err != nil {
+		l.cancel()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package dvls
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(10, 2, now)
+
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, limiter.reserve(now))
+
+	// After a second the bucket is refilled, but never above the burst size.
+	later := now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), limiter.reserve(later))
+	assert.Equal(t, time.Duration(0), limiter.reserve(later))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(later))
+}
+
+func TestRateLimiter_WaitRespectsContext(t *testing.T) {
+	limiter := newRateLimiter(0.001, 1, time.Now())
+	require.NoError(t, limiter.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := limiter.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRequest_RateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	client := newTestClient(t, mux)
+	client.limiter = &requestLimiter{rate: newRateLimiter(50, 1, time.Now())}
+
+	start := time.Now()
+	for range 4 {
+		_, err := client.Vaults.Get("test")
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRequest_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	client := newTestClient(t, mux)
+	cfg := clientConfig{maxConcurrentRequests: 2}
+	limiter, err := cfg.buildRequestLimiter()
+	require.NoError(t, err)
+	client.limiter = limiter
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := client.Vaults.Get("test")
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestRequest_WaitingForSlotRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	client := newTestClient(t, mux)
+	client.limiter = &requestLimiter{slots: make(chan struct{}, 1)}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = client.Vaults.Get("test")
+	}()
+	require.Eventually(t, func() bool { return len(client.limiter.slots) == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Vaults.GetWithContext(ctx, "test")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	<-done
+}
+
+func TestNewClient_InvalidLimits(t *testing.T) {
+	_, err := NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithRateLimit(10, 0))
+	assert.ErrorContains(t, err, "invalid client options")
+
+	_, err = NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithMaxConcurrentRequests(-1))
+	assert.ErrorContains(t, err, "invalid client options")
+
+	client, err := NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithRateLimit(10, 5), WithMaxConcurrentRequests(4))
+	require.NoError(t, err)
+	assert.Equal(t, 4, cap(client.limiter.slots))
+}
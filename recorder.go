@@ -0,0 +1,300 @@
+package dvls
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// RecorderMode selects whether a Recorder records or replays DVLS exchanges.
+type RecorderMode int
+
+const (
+	// RecorderModeAuto replays the cassette when its file exists and records it otherwise.
+	RecorderModeAuto RecorderMode = iota
+	// RecorderModeRecord sends the requests to DVLS and records the exchanges, replacing the cassette.
+	RecorderModeRecord
+	// RecorderModeReplay answers the requests from the cassette without any network access.
+	RecorderModeReplay
+)
+
+// ErrNoRecordedInteraction is returned by a replaying Recorder for requests absent from its cassette.
+var ErrNoRecordedInteraction = errors.New("no recorded interaction matches the request")
+
+// Recorder is an http.RoundTripper that records DVLS exchanges into a cassette file and replays them
+// offline, so that tests written against a live instance can run without one. Use it with WithTransport.
+//
+// Requests are matched on their method, templated path, such as /api/v1/vault/{vaultId}, query, and body.
+// Recorded interactions are replayed in order: each one answers a single request.
+//
+// Cassettes are scrubbed as they are recorded. Request headers, including the session token, are not
+// recorded, and secrets are redacted from the request bodies, login credentials included. Secrets are
+// redacted from the response bodies as well, except the values previously sent by the client itself,
+// such as the password of an entry it created, so that tests can check them on replay. Response bodies
+// that are not JSON, such as documents, are only kept when the client uploaded them.
+type Recorder struct {
+	path      string
+	mode      RecorderMode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+	// sent holds the secrets and documents sent by the client while recording.
+	sent map[string]bool
+}
+
+type cassette struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// Encoding is "base64" for bodies that are not valid UTF-8.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// NewRecorder returns a Recorder using the cassette at path. When recording, requests are sent through
+// transport, or http.DefaultTransport when it is nil, and the cassette is written by Stop.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{path: path, mode: mode, transport: transport, sent: make(map[string]bool)}
+
+	if mode == RecorderModeAuto {
+		r.mode = RecorderModeReplay
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			r.mode = RecorderModeRecord
+		}
+	}
+
+	if r.mode == RecorderModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns RecorderModeRecord or RecorderModeReplay, the mode RecorderModeAuto resolves to.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// Stop writes the cassette when recording. It does nothing when replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	if r.mode == RecorderModeReplay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			Path:   matchEndpointTemplate(req.URL.Path),
+			Query:  req.URL.RawQuery,
+			Body:   string(r.scrubRequestBody(req.URL.Path, body)),
+		},
+		Response: recordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+	}
+	recorded.Response.Body, recorded.Response.Encoding = encodeBody(r.scrubResponseBody(respBody))
+	r.cassette.Interactions = append(r.cassette.Interactions, recorded)
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	method, path, scrubbed := req.Method, matchEndpointTemplate(req.URL.Path), string(redactBody(body))
+	query := req.URL.Query()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, recorded := range r.cassette.Interactions {
+		if r.used[i] || recorded.Request.Method != method || recorded.Request.Path != path || recorded.Request.Body != scrubbed {
+			continue
+		}
+		if !sameQuery(recorded.Request.Query, query) {
+			continue
+		}
+		r.used[i] = true
+
+		respBody, err := decodeBody(recorded.Response.Body, recorded.Response.Encoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recorded response body: %w", err)
+		}
+
+		header := make(http.Header)
+		if recorded.Response.ContentType != "" {
+			header.Set("Content-Type", recorded.Response.ContentType)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Response.StatusCode, http.StatusText(recorded.Response.StatusCode)),
+			StatusCode:    recorded.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRecordedInteraction, method, path)
+}
+
+// sameQuery reports whether a recorded query holds the same parameters as query, in any order, so that
+// the pages of a listing fetched concurrently are answered with their own recorded page.
+func sameQuery(recorded string, query url.Values) bool {
+	values, err := url.ParseQuery(recorded)
+	if err != nil {
+		return false
+	}
+
+	return maps.EqualFunc(values, query, slices.Equal)
+}
+
+// scrubRequestBody redacts a request body, remembering the secrets and documents it carries so that
+// they are kept in the responses. Login credentials are not remembered. r.mu must be held.
+func (r *Recorder) scrubRequestBody(path string, body []byte) []byte {
+	endpoint := matchEndpointTemplate(path)
+	if endpoint == loginEndpoint || endpoint == userLoginEndpoint {
+		return redactBody(body)
+	}
+
+	if len(body) > 0 && !json.Valid(body) {
+		r.sent[string(body)] = true
+	}
+
+	return redactBodyExcept(body, func(secret string) bool {
+		r.sent[secret] = true
+		return false
+	})
+}
+
+// scrubResponseBody redacts a response body, except for the values sent by the client. r.mu must be held.
+func (r *Recorder) scrubResponseBody(body []byte) []byte {
+	if len(body) > 0 && !json.Valid(body) {
+		if r.sent[string(body)] {
+			return body
+		}
+		return []byte(redacted)
+	}
+
+	return redactBodyExcept(body, func(secret string) bool { return r.sent[secret] })
+}
+
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body string, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
@@ -0,0 +1,162 @@
+package dvls
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRecorderVaultID = "3f2504e0-4f89-11d3-9a0c-0305e82c3301"
+
+func newRecorderMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("GET /api/v1/vault/{vaultId}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Vault{Id: r.PathValue("vaultId"), Name: "recorded"})
+	})
+	mux.HandleFunc("POST /api/v1/vault/{vaultId}/entry", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"entry-1"}`))
+	})
+	mux.HandleFunc("GET /api/v1/vault/{vaultId}/entry/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"entry-1","name":"db","type":"Credential","subType":"Default",` +
+			`"data":{"username":"admin","password":"fixture-password"},` +
+			`"tokenId":"server-secret"}`))
+	})
+
+	return mux
+}
+
+func recordedCalls(t *testing.T, client *Client) (Vault, Entry) {
+	t.Helper()
+
+	vault, err := client.Vaults.Get(testRecorderVaultID)
+	require.NoError(t, err)
+
+	id, err := client.Entries.Credential.New(Entry{
+		VaultId: testRecorderVaultID,
+		Name:    "db",
+		Type:    EntryCredentialType,
+		SubType: EntryCredentialSubTypeDefault,
+		Data:    EntryCredentialDefaultData{Username: "admin", Password: "fixture-password"},
+	})
+	require.NoError(t, err)
+
+	entry, err := client.Entries.Credential.GetById(testRecorderVaultID, id)
+	require.NoError(t, err)
+
+	return vault, entry
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "recorder.json")
+
+	server := httptest.NewServer(newRecorderMux(t))
+	defer server.Close()
+
+	recorder, err := NewRecorder(path, RecorderModeAuto, nil)
+	require.NoError(t, err)
+	require.Equal(t, RecorderModeRecord, recorder.Mode())
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithTransport(recorder))
+	require.NoError(t, err)
+	recordedVault, recordedEntry := recordedCalls(t, client)
+	require.NoError(t, recorder.Stop())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	cassette := string(data)
+	assert.NotContains(t, cassette, "mock-token-123")
+	assert.NotContains(t, cassette, "test-secret")
+	assert.NotContains(t, cassette, "server-secret")
+	assert.Contains(t, cassette, "fixture-password")
+	assert.Contains(t, cassette, "/api/v1/vault/{vaultId}/entry/{id}")
+
+	server.Close()
+
+	replayer, err := NewRecorder(path, RecorderModeAuto, nil)
+	require.NoError(t, err)
+	require.Equal(t, RecorderModeReplay, replayer.Mode())
+
+	client, err = NewClient("test-key", "test-secret", "https://dvls.invalid", WithTransport(replayer))
+	require.NoError(t, err)
+	vault, entry := recordedCalls(t, client)
+	assert.Equal(t, recordedVault, vault)
+	assert.Equal(t, recordedEntry.Data, entry.Data)
+
+	_, err = client.Vaults.Get(testRecorderVaultID)
+	assert.ErrorIs(t, err, ErrNoRecordedInteraction)
+}
+
+func TestRecorder_ReplayMatchesBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recorder.json")
+
+	server := httptest.NewServer(newRecorderMux(t))
+	defer server.Close()
+
+	recorder, err := NewRecorder(path, RecorderModeRecord, nil)
+	require.NoError(t, err)
+	client, err := NewClient("test-key", "test-secret", server.URL, WithTransport(recorder))
+	require.NoError(t, err)
+	recordedCalls(t, client)
+	require.NoError(t, recorder.Stop())
+
+	replayer, err := NewRecorder(path, RecorderModeReplay, nil)
+	require.NoError(t, err)
+	client, err = NewClient("test-key", "test-secret", "https://dvls.invalid", WithTransport(replayer))
+	require.NoError(t, err)
+
+	_, err = client.Entries.Credential.New(Entry{
+		VaultId: testRecorderVaultID,
+		Name:    "other",
+		Type:    EntryCredentialType,
+		SubType: EntryCredentialSubTypeDefault,
+		Data:    EntryCredentialDefaultData{Username: "admin"},
+	})
+	assert.ErrorIs(t, err, ErrNoRecordedInteraction)
+}
+
+func TestRecorder_ReplayMatchesQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recorder.json")
+
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("GET /api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		json.NewEncoder(w).Encode(vaultListResponse{Data: []Vault{{Id: "vault-" + page}}, TotalPage: 8})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	recorder, err := NewRecorder(path, RecorderModeRecord, nil)
+	require.NoError(t, err)
+	client, err := NewClient("test-key", "test-secret", server.URL, WithTransport(recorder))
+	require.NoError(t, err)
+	recorded, err := client.Vaults.List()
+	require.NoError(t, err)
+	require.NoError(t, recorder.Stop())
+
+	replayer, err := NewRecorder(path, RecorderModeReplay, nil)
+	require.NoError(t, err)
+	client, err = NewClient("test-key", "test-secret", "https://dvls.invalid", WithTransport(replayer), WithPageConcurrency(4))
+	require.NoError(t, err)
+
+	vaults, err := client.Vaults.List()
+	require.NoError(t, err)
+	assert.Equal(t, recorded, vaults)
+	for i, vault := range vaults {
+		assert.Equal(t, "vault-"+strconv.Itoa(i+1), vault.Id)
+	}
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), RecorderModeReplay, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package dvls
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// redacted replaces secrets in logs and error bodies.
+const redacted = "[REDACTED]"
+
+// secretKeys lists, in lower case, the JSON keys whose values are secrets.
+var secretKeys = map[string]bool{
+	"password":                   true,
+	"sensitivedata":              true,
+	"tokenid":                    true,
+	"token":                      true,
+	"appsecret":                  true,
+	"secret":                     true,
+	"apikey":                     true,
+	"clientsecret":               true,
+	"connectionstring":           true,
+	"privatekeydata":             true,
+	"privatekeypassphrase":       true,
+	"privatekeyoverridepassword": true,
+}
+
+// isSecretKey reports whether a JSON key holds a secret.
+func isSecretKey(key string) bool {
+	return secretKeys[strings.ToLower(key)]
+}
+
+// redactBody returns a copy of a request or response body with the values of secret JSON keys replaced.
+// JSON documents nested in string values, as used by the legacy entry endpoints, are redacted as well.
+// Bodies that are not JSON are entirely replaced, since their content cannot be inspected.
+func redactBody(body []byte) []byte {
+	return redactBodyExcept(body, nil)
+}
+
+// redactBodyExcept is redactBody, except that the secret strings for which keep returns true are left
+// in place. keep may be nil.
+func redactBodyExcept(body []byte, keep func(string) bool) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return []byte(redacted)
+	}
+
+	redactedBody, err := json.Marshal(redactValue(value, keep))
+	if err != nil {
+		return []byte(redacted)
+	}
+
+	return redactedBody
+}
+
+func redactValue(value any, keep func(string) bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSecretKey(key) {
+				v[key] = redactSecret(field, keep)
+				continue
+			}
+			v[key] = redactValue(field, keep)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, keep)
+		}
+		return v
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			return v
+		}
+		var nested any
+		if err := json.Unmarshal([]byte(trimmed), &nested); err != nil {
+			return v
+		}
+		nestedBody, err := json.Marshal(redactValue(nested, keep))
+		if err != nil {
+			return redacted
+		}
+		return string(nestedBody)
+	default:
+		return v
+	}
+}
+
+// redactSecret redacts the value of a secret key. Objects and arrays keep their structure and have their
+// strings and numbers redacted. Redacted numbers become the string [REDACTED], so the redacted document
+// only decodes into the same types when its secrets are strings.
+func redactSecret(value any, keep func(string) bool) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" || (keep != nil && keep(v)) {
+			return v
+		}
+		return redacted
+	case json.Number:
+		return redacted
+	case map[string]any:
+		for key, field := range v {
+			v[key] = redactSecret(field, keep)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactSecret(item, keep)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isSensitiveEndpoint reports whether the responses of an endpoint consist of secrets, such as the
+// /sensitive-data endpoints, and must never be logged.
+func isSensitiveEndpoint(endpoint string) bool {
+	return strings.HasSuffix(endpoint, "/sensitive-data")
+}
@@ -0,0 +1,61 @@
+package dvls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WithMaxResponseSize limits the size of the response bodies read from DVLS, documents included. Larger
+// responses fail with a *ResponseTooLargeError instead of being read into memory. A zero size, the
+// default, sets no limit.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.maxResponseSize = size
+	}
+}
+
+// ResponseTooLargeError is returned when a response body exceeds the size set with WithMaxResponseSize.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the maximum size of %d bytes", e.Limit)
+}
+
+// IsResponseTooLarge reports whether err is caused by a response body exceeding WithMaxResponseSize.
+func IsResponseTooLarge(err error) bool {
+	var tooLarge *ResponseTooLargeError
+	return errors.As(err, &tooLarge)
+}
+
+// maxSizeReader reads from r and fails with a *ResponseTooLargeError once more than limit bytes are read.
+type maxSizeReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func newMaxSizeReader(r io.Reader, limit int64) *maxSizeReader {
+	return &maxSizeReader{r: r, limit: limit, remaining: limit}
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = 0
+		return n, &ResponseTooLargeError{Limit: m.limit}
+	}
+	m.remaining -= int64(n)
+
+	return n, err
+}
+
+// keepsResponseBody reports whether the response bodies must be read into Response.Response because the
+// logger or the hooks see them. Otherwise, responses are decoded as a stream when a target is provided.
+func (c *Client) keepsResponseBody(ctx context.Context) bool {
+	return c.logBodies(ctx) || (c.hookSecrets && len(c.hooks) > 0)
+}
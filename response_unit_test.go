@@ -0,0 +1,178 @@
+package dvls
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVaultMux(t *testing.T, body string, chunked bool) *http.ServeMux {
+	t.Helper()
+
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("GET /api/v1/vault/{vaultId}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !chunked {
+			w.Write([]byte(body))
+			return
+		}
+
+		// Flushing before writing the rest sends the body without a Content-Length.
+		w.Write([]byte(body[:1]))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(body[1:]))
+	})
+
+	return mux
+}
+
+func TestResponse_StreamedDecode(t *testing.T) {
+	var hookBody []byte
+	server := httptest.NewServer(newVaultMux(t, `{"id":"vault-1","name":"Alpha"}`, false))
+	defer server.Close()
+
+	hooks := Hooks{AfterResponse: func(ctx context.Context, info ResponseInfo) {
+		if strings.Contains(info.URL, "/vault/") {
+			hookBody = info.Response.Response
+		}
+	}}
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithHooks(hooks))
+	require.NoError(t, err)
+
+	vault, err := client.Vaults.Get("vault-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", vault.Name)
+
+	client, err = NewClient("test-key", "test-secret", server.URL, WithHooks(hooks), WithHookSecrets())
+	require.NoError(t, err)
+
+	vault, err = client.Vaults.Get("vault-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", vault.Name)
+	assert.JSONEq(t, `{"id":"vault-1","name":"Alpha"}`, string(hookBody))
+}
+
+func TestResponse_StreamedDecodeError(t *testing.T) {
+	server := httptest.NewServer(newVaultMux(t, `{"id":`, false))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+
+	_, err = client.Vaults.Get("vault-1")
+	assert.ErrorContains(t, err, "failed to unmarshal response body")
+}
+
+func TestResponse_MaxSize(t *testing.T) {
+	body := `{"id":"vault-1","name":"` + strings.Repeat("a", 100) + `"}`
+
+	tests := []struct {
+		name    string
+		chunked bool
+		limit   int64
+		tooBig  bool
+	}{
+		{name: "content length", limit: 64, tooBig: true},
+		{name: "chunked", chunked: true, limit: 64, tooBig: true},
+		{name: "within limit", chunked: true, limit: int64(len(body))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(newVaultMux(t, body, tt.chunked))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret", server.URL, WithMaxResponseSize(tt.limit))
+			require.NoError(t, err)
+
+			_, err = client.Vaults.Get("vault-1")
+			if !tt.tooBig {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, IsResponseTooLarge(err))
+			var tooLarge *ResponseTooLargeError
+			require.ErrorAs(t, err, &tooLarge)
+			assert.Equal(t, tt.limit, tooLarge.Limit)
+		})
+	}
+}
+
+func TestNewClient_InvalidMaxResponseSize(t *testing.T) {
+	_, err := NewClient("test-key", "test-secret", "http://localhost", WithLazyLogin(), WithMaxResponseSize(-1))
+	assert.ErrorContains(t, err, "invalid client options")
+}
+
+func TestResponse_StreamedLegacyDecode(t *testing.T) {
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("GET /api/connections/partial/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "denied" {
+			w.Write([]byte(`{"result":2,"message":"denied"}`))
+			return
+		}
+		w.Write([]byte(`{"result":1,"data":{"id":"cert-1","name":"certificate","data":{"dataMode":2,"fileName":"cert.pem"}}}`))
+	})
+	mux.HandleFunc("POST /api/connections/partial/{id}/sensitive-data", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":1,"data":"{\"data\":{\"password\":{\"hasSensitiveData\":true,\"sensitiveData\":\"certpass\"}}}"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, opts := range [][]ClientOption{nil, {WithHooks(Hooks{}), WithHookSecrets()}} {
+		client, err := NewClient("test-key", "test-secret", server.URL, opts...)
+		require.NoError(t, err)
+
+		entry, err := client.Entries.Certificate.Get("cert-1")
+		require.NoError(t, err)
+		assert.Equal(t, "certificate", entry.Name)
+		assert.Equal(t, "cert.pem", entry.CertificateIdentifier)
+		assert.Equal(t, EntryCertificateDataModeFile, entry.GetDataMode())
+
+		entry, err = client.Entries.Certificate.GetPassword(entry)
+		require.NoError(t, err)
+		assert.Equal(t, "certpass", entry.Password)
+
+		_, err = client.Entries.Certificate.Get("denied")
+		var resultErr *ResultError
+		require.ErrorAs(t, err, &resultErr)
+		assert.Equal(t, SaveResultAccessDenied, resultErr.Result)
+		assert.Equal(t, "denied", resultErr.Message)
+	}
+}
+
+func TestResponse_FailedResultWithEmptyData(t *testing.T) {
+	denied := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":2,"message":"denied","data":""}`))
+	}
+	mux := newLoginMux(t, nil)
+	mux.HandleFunc("GET /api/connections/partial/{id}", denied)
+	mux.HandleFunc("GET /api/private-instance-information", denied)
+	mux.HandleFunc("GET /api/configuration/timezones", denied)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, opts := range [][]ClientOption{nil, {WithHooks(Hooks{}), WithHookSecrets()}} {
+		client, err := NewClient("test-key", "test-secret", server.URL, opts...)
+		require.NoError(t, err)
+
+		_, err = client.Entries.Host.Get("host-1")
+		assert.ErrorIs(t, err, ErrResultAccessDenied)
+		_, err = client.Entries.Website.Get("website-1")
+		assert.ErrorIs(t, err, ErrResultAccessDenied)
+		_, err = client.Entries.Certificate.Get("cert-1")
+		assert.ErrorIs(t, err, ErrResultAccessDenied)
+		_, err = client.GetPrivateServerInfo()
+		assert.ErrorIs(t, err, ErrResultAccessDenied)
+		_, err = client.GetServerTimezones()
+		assert.ErrorIs(t, err, ErrResultAccessDenied)
+	}
+}
@@ -0,0 +1,36 @@
+package dvls
+
+import "fmt"
+
+// ResultError is returned when a DVLS response reports a SaveResult other than SaveResultSuccess.
+// It can be matched against the ErrResult sentinels with errors.Is, for example
+// errors.Is(err, ErrResultAccessDenied).
+type ResultError struct {
+	Result  SaveResult
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("unexpected result code %d (%s) %s", e.Result, e.Result, e.Message)
+}
+
+// Is reports whether target is a ResultError with the same result code, ignoring the message.
+func (e *ResultError) Is(target error) bool {
+	t, ok := target.(*ResultError)
+	return ok && t.Result == e.Result
+}
+
+// Sentinel errors matching each failing SaveResult through errors.Is.
+var (
+	ErrResultError                      error = &ResultError{Result: SaveResultError}
+	ErrResultAccessDenied               error = &ResultError{Result: SaveResultAccessDenied}
+	ErrResultInvalidData                error = &ResultError{Result: SaveResultInvalidData}
+	ErrResultAlreadyExists              error = &ResultError{Result: SaveResultAlreadyExists}
+	ErrResultMaximumReached             error = &ResultError{Result: SaveResultMaximumReached}
+	ErrResultNotFound                   error = &ResultError{Result: SaveResultNotFound}
+	ErrResultLicenseExpired             error = &ResultError{Result: SaveResultLicenseExpired}
+	ErrResultUnknown                    error = &ResultError{Result: SaveResultUnknown}
+	ErrResultTwoFactorTypeNotConfigured error = &ResultError{Result: SaveResultTwoFactorTypeNotConfigured}
+	ErrResultWebApiRedirectToLogin      error = &ResultError{Result: SaveResultWebApiRedirectToLogin}
+	ErrResultDuplicateLoginEmail        error = &ResultError{Result: SaveResultDuplicateLoginEmail}
+)
@@ -0,0 +1,98 @@
+package dvls
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRespSaveResult_ReturnsResultError(t *testing.T) {
+	resp := Response{Result: uint8(SaveResultAccessDenied), Message: "no access"}
+
+	err := resp.CheckRespSaveResult()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrResultAccessDenied)
+	assert.NotErrorIs(t, err, ErrResultNotFound)
+
+	var resultErr *ResultError
+	require.ErrorAs(t, err, &resultErr)
+	assert.Equal(t, SaveResultAccessDenied, resultErr.Result)
+	assert.Equal(t, "no access", resultErr.Message)
+	assert.Equal(t, "unexpected result code 2 (AccessDenied) no access", err.Error())
+
+	assert.NoError(t, Response{Result: uint8(SaveResultSuccess)}.CheckRespSaveResult())
+}
+
+func TestRequest_DecodesAPIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorCode":"ValidationFailed","message":"The request is invalid","errors":{"name":["The name is required"]}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{baseUri: server.URL, client: server.Client(), tokens: tokenManager{token: "test-token"}}
+
+	_, err := client.Request(server.URL+"/api/v1/vault", http.MethodPost, nil)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "ValidationFailed", apiErr.Code)
+	assert.Equal(t, "The request is invalid", apiErr.Message)
+	assert.Equal(t, map[string][]string{"name": {"The name is required"}}, apiErr.Details)
+
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Contains(t, string(reqErr.Body), "ValidationFailed")
+}
+
+func TestParseAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *APIError
+	}{
+		{
+			name: "numeric code",
+			body: `{"code":404,"message":"Vault not found"}`,
+			want: &APIError{StatusCode: http.StatusNotFound, Code: "404", Message: "Vault not found"},
+		},
+		{
+			name: "problem details",
+			body: `{"title":"One or more validation errors occurred.","errors":{"id":["Invalid id"]}}`,
+			want: &APIError{StatusCode: http.StatusNotFound, Message: "One or more validation errors occurred.", Details: map[string][]string{"id": {"Invalid id"}}},
+		},
+		{name: "empty object", body: `{}`},
+		{name: "not json", body: `Not Found`},
+		{name: "empty body", body: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseAPIError(http.StatusNotFound, []byte(tt.body)))
+		})
+	}
+}
+
+func TestRequest_PlainStatusErrorIsNotAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := &Client{baseUri: server.URL, client: server.Client(), tokens: tokenManager{token: "test-token"}}
+
+	_, err := client.Request(server.URL+"/api/v1/vault", http.MethodGet, nil)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	assert.False(t, errors.As(err, &apiErr))
+}
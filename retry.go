@@ -0,0 +1,197 @@
+package dvls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that failed with a transport error or a retryable status code are retried.
+// Idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried; POST requests are only retried when
+// RetryPost is set.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles after each attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, including the delays requested by the server with
+	// a Retry-After header. A zero value means no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each delay that is randomized.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that trigger a retry.
+	RetryableStatusCodes []int
+	// RetryPost allows POST requests to be retried. Only enable it when the endpoints used are safe to replay.
+	RetryPost bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suited to transient DVLS failures such as restarts and throttling.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables retries of failed requests according to policy. Requests are not retried by default.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.retryPolicy = &policy
+	}
+}
+
+// allowsMethod reports whether requests using method may be retried.
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return p.RetryPost
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether the error returned by an attempt is worth retrying.
+func (p RetryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+
+	if reqErr.StatusCode != 0 {
+		return slices.Contains(p.RetryableStatusCodes, reqErr.StatusCode)
+	}
+
+	var urlErr *url.Error
+	return errors.As(reqErr.Err, &urlErr)
+}
+
+// maxDelay is the longest delay that can be represented.
+const maxDelay = time.Duration(math.MaxInt64)
+
+// backoff returns the delay before the given retry, starting at 1 for the first retry.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < retry; i++ {
+		// Without a cap, stop doubling before the delay overflows.
+		if delay > maxDelay/2 {
+			delay = maxDelay
+			break
+		}
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		delay -= time.Duration(jitter * rand.Float64() * float64(delay))
+	}
+
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header value expressed either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > int(maxDelay/time.Second) {
+			return maxDelay, true
+		}
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// retryDelay returns the delay before the given retry, honoring the Retry-After header sent with the error
+// up to MaxBackoff.
+func (p RetryPolicy) retryDelay(retry int, err error) time.Duration {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) && reqErr.header != nil {
+		if delay, ok := parseRetryAfter(reqErr.header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxBackoff > 0 {
+				delay = min(delay, p.MaxBackoff)
+			}
+			return delay
+		}
+	}
+
+	return p.backoff(retry)
+}
+
+// sleepWithContext waits for the delay to elapse or for the context to be done.
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sendRequestWithContext sends a request authenticated with the given token, retrying it according to the
+// client RetryPolicy.
+func (c *Client) sendRequestWithContext(ctx context.Context, token string, url string, reqMethod string, contentType string, reqBody io.Reader, options ...RequestOptions) (Response, error) {
+	if c.tokens.isClosed() {
+		return Response{}, &RequestError{Err: ErrClientClosed, Url: url}
+	}
+
+	policy := c.retryPolicy
+	if policy == nil || policy.MaxAttempts < 2 || !policy.allowsMethod(reqMethod) {
+		return c.sendAttemptWithContext(ctx, token, url, reqMethod, contentType, reqBody, options...)
+	}
+
+	body, err := readRequestBody(reqBody)
+	if err != nil {
+		return Response{}, &RequestError{Err: fmt.Errorf("failed to read request body: %w", err), Url: url}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendAttemptWithContext(ctx, token, url, reqMethod, contentType, newBodyReader(body), options...)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(ctx, err) {
+			return resp, err
+		}
+
+		if sleepErr := sleepWithContext(ctx, policy.retryDelay(attempt, err)); sleepErr != nil {
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package dvls
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestClient(t *testing.T, mux *http.ServeMux, policy RetryPolicy) *Client {
+	t.Helper()
+
+	client := newTestClient(t, mux)
+	client.retryPolicy = &policy
+
+	return client
+}
+
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+
+	return policy
+}
+
+func TestRetry_RetryableStatusCode(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	client := newRetryTestClient(t, mux, fastRetryPolicy())
+
+	vault, err := client.Vaults.Get("test")
+	require.NoError(t, err)
+	assert.Equal(t, "test", vault.Id)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	policy := fastRetryPolicy()
+	policy.MaxAttempts = 3
+	client := newRetryTestClient(t, mux, policy)
+
+	_, err := client.Vaults.Get("test")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_NonRetryableStatusCode(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	client := newRetryTestClient(t, mux, fastRetryPolicy())
+
+	_, err := client.Vaults.Get("test")
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_PostIsOptIn(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		var req vaultRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "created", req.Name)
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"vault-id","name":"created"}`))
+	})
+
+	client := newRetryTestClient(t, mux, fastRetryPolicy())
+
+	_, err := client.Vaults.New(Vault{Name: "created"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	policy := fastRetryPolicy()
+	policy.RetryPost = true
+	client.retryPolicy = &policy
+	atomic.StoreInt32(&calls, 0)
+
+	vault, err := client.Vaults.New(Vault{Name: "created"})
+	require.NoError(t, err)
+	assert.Equal(t, "vault-id", vault.Id)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Minute
+	client := newRetryTestClient(t, mux, policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.Vaults.GetWithContext(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_TransportError(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	client := newRetryTestClient(t, mux, fastRetryPolicy())
+
+	_, err := client.Vaults.Get("test")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetry_StopsOnContextCancellation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Minute
+	client := newRetryTestClient(t, mux, policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Vaults.GetWithContext(ctx, "test")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRetry_PaginationSurvivesTransientFailure(t *testing.T) {
+	var page2Calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "2" && atomic.AddInt32(&page2Calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		json.NewEncoder(w).Encode(vaultListResponse{
+			Data:        []Vault{{Id: "vault-" + page}},
+			CurrentPage: 1,
+			TotalPage:   2,
+		})
+	})
+
+	client := newRetryTestClient(t, mux, fastRetryPolicy())
+
+	vaults, err := client.Vaults.List()
+	require.NoError(t, err)
+	require.Len(t, vaults, 2)
+	assert.Equal(t, "vault-2", vaults[1].Id)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("3", now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(10*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+
+	delay, ok = parseRetryAfter("99999999999999999", now)
+	assert.True(t, ok)
+	assert.Equal(t, maxDelay, delay)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(10))
+
+	policy.MaxBackoff = 0
+	assert.Equal(t, maxDelay, policy.backoff(100))
+
+	policy.Jitter = 0.5
+	policy.MaxBackoff = 300 * time.Millisecond
+	for range 20 {
+		delay := policy.backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 100*time.Millisecond)
+	}
+}
+
+func TestRetryPolicyRetryDelay(t *testing.T) {
+	err := &RequestError{StatusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"3600"}}}
+
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+	assert.Equal(t, 2*time.Second, policy.retryDelay(1, err))
+	assert.Equal(t, 100*time.Millisecond, policy.retryDelay(1, &RequestError{StatusCode: http.StatusServiceUnavailable}))
+
+	policy.MaxBackoff = 0
+	assert.Equal(t, time.Hour, policy.retryDelay(1, err))
+}
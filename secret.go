@@ -0,0 +1,140 @@
+package dvls
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// SecretString holds a secret value, such as a password or an API key, that must not end up in logs.
+// It prints as [REDACTED] with the fmt package, log/slog and encoders relying on encoding.TextMarshaler,
+// such as encoding/json. The value is only available through Reveal.
+//
+// Copies of a SecretString share the same value, so Wipe empties every copy.
+type SecretString struct {
+	value *[]byte
+}
+
+var (
+	_ fmt.Formatter  = SecretString{}
+	_ fmt.Stringer   = SecretString{}
+	_ slog.LogValuer = SecretString{}
+)
+
+// NewSecretString returns a SecretString holding value.
+func NewSecretString(value string) SecretString {
+	b := []byte(value)
+	return SecretString{value: &b}
+}
+
+// Reveal returns the secret value.
+func (s SecretString) Reveal() string {
+	if s.value == nil {
+		return ""
+	}
+
+	return string(*s.value)
+}
+
+// IsEmpty reports whether the secret value is empty.
+func (s SecretString) IsEmpty() bool {
+	return s.value == nil || len(*s.value) == 0
+}
+
+// Wipe overwrites the secret value with zeros and empties s and its copies. It is a best effort: strings
+// previously returned by Reveal, or from which the SecretString was created, are not cleared.
+func (s *SecretString) Wipe() {
+	if s.value == nil {
+		return
+	}
+
+	clear(*s.value)
+	*s.value = nil
+}
+
+// String implements the fmt.Stringer interface.
+func (s SecretString) String() string {
+	return redacted
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (s SecretString) GoString() string {
+	return redacted
+}
+
+// Format implements the fmt.Formatter interface. Every verb prints [REDACTED].
+func (s SecretString) Format(f fmt.State, verb rune) {
+	f.Write([]byte(redacted))
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (s SecretString) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s SecretString) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, so that secrets can be loaded from
+// configuration files. Note that marshaling the SecretString again yields [REDACTED].
+func (s *SecretString) UnmarshalText(text []byte) error {
+	b := append([]byte(nil), text...)
+	s.value = &b
+	return nil
+}
+
+// redactor is implemented by the entry types holding secrets. redacted returns a copy of the value, of a
+// type without the redacting methods, whose secrets are replaced by [REDACTED]. The entry types use it to
+// implement fmt.Formatter and slog.LogValuer, so that printing or logging them does not leak secrets.
+type redactor interface {
+	redacted() any
+}
+
+// redactString returns [REDACTED] for a non-empty secret, and the empty string otherwise.
+func redactString(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// redactStringPointer is redactString for the secrets that are nil when they were not fetched.
+func redactStringPointer(secret *string) *string {
+	if secret == nil {
+		return nil
+	}
+
+	r := redactString(*secret)
+	return &r
+}
+
+// redactedValue returns the redacted copy of value when it holds secrets, and value otherwise.
+func redactedValue(value any) any {
+	r, ok := value.(redactor)
+	if !ok {
+		return value
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+		return value
+	}
+
+	return r.redacted()
+}
+
+// formatRedacted formats the redacted copy of a value holding secrets with the verb and flags of f. The
+// Go syntax representation keeps the name of the original type.
+func formatRedacted(f fmt.State, verb rune, r redactor) {
+	value := r.redacted()
+	if verb != 'v' || !f.Flag('#') {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), value)
+		return
+	}
+
+	goSyntax := fmt.Sprintf("%#v", value)
+	io.WriteString(f, strings.Replace(goSyntax, reflect.TypeOf(value).String(), reflect.TypeOf(r).String(), 1))
+}
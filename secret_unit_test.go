@@ -0,0 +1,121 @@
+package dvls
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretString_Redacts(t *testing.T) {
+	secret := NewSecretString("hunter2")
+	wrapper := struct {
+		Name     string
+		Password SecretString
+	}{Name: "entry", Password: secret}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		assert.NotContains(t, fmt.Sprintf(format, secret), "hunter2", format)
+		assert.NotContains(t, fmt.Sprintf(format, wrapper), "hunter2", format)
+	}
+	assert.Equal(t, redacted, fmt.Sprint(secret))
+	assert.Equal(t, "{entry [REDACTED]}", fmt.Sprintf("%v", wrapper))
+
+	jsonBody, err := json.Marshal(wrapper)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Name":"entry","Password":"[REDACTED]"}`, string(jsonBody))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("entry", "password", secret, "entry", wrapper)
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), `"password":"[REDACTED]"`)
+
+	buf.Reset()
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("entry", "password", secret)
+	assert.Contains(t, buf.String(), "password=[REDACTED]")
+
+	assert.Equal(t, "hunter2", secret.Reveal())
+}
+
+func TestSecretString_Wipe(t *testing.T) {
+	secret := NewSecretString("hunter2")
+	backing := *secret.value
+	copied := secret
+
+	secret.Wipe()
+	assert.True(t, secret.IsEmpty())
+	assert.Empty(t, secret.Reveal())
+	assert.Equal(t, make([]byte, len("hunter2")), backing)
+	assert.True(t, copied.IsEmpty())
+	assert.Empty(t, copied.Reveal())
+
+	var zero SecretString
+	zero.Wipe()
+	assert.True(t, zero.IsEmpty())
+}
+
+func TestSecretString_UnmarshalText(t *testing.T) {
+	var config struct {
+		AppSecret SecretString `json:"appSecret"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"appSecret":"s3cret"}`), &config))
+	assert.Equal(t, "s3cret", config.AppSecret.Reveal())
+}
+
+func TestEntrySecretAccessors(t *testing.T) {
+	password := "hunter2"
+
+	assert.Equal(t, password, EntryCredentialDefaultData{Password: password}.SecretPassword().Reveal())
+	assert.Equal(t, password, EntryCredentialApiKeyData{ApiKey: password}.SecretApiKey().Reveal())
+	assert.Equal(t, password, EntryCertificate{Password: password}.SecretPassword().Reveal())
+	assert.Equal(t, password, EntryHostAuthDetails{Password: &password}.SecretPassword().Reveal())
+	assert.True(t, EntryWebsiteAuthDetails{}.SecretPassword().IsEmpty())
+	assert.Equal(t, password, EntryCredentialConnectionStringData{ConnectionString: password}.SecretConnectionString().Reveal())
+}
+
+func TestEntryTypes_Redact(t *testing.T) {
+	secret := "hunter2"
+
+	values := []any{
+		EntryCredentialAccessCodeData{Password: secret},
+		EntryCredentialApiKeyData{ApiId: "api", ApiKey: secret},
+		EntryCredentialAzureServicePrincipalData{ClientId: "client", ClientSecret: secret},
+		EntryCredentialConnectionStringData{ConnectionString: secret},
+		EntryCredentialDefaultData{Username: "admin", Password: secret},
+		EntryCredentialPrivateKeyData{Password: secret, PrivateKey: secret, Passphrase: secret},
+		Entry{Name: "db", Data: &EntryCredentialDefaultData{Username: "admin", Password: secret}},
+		Entry{Name: "db", Data: (*EntryCredentialDefaultData)(nil)},
+		EntryCertificate{Name: "cert", Password: secret},
+		EntryHost{EntryName: "host", HostDetails: EntryHostAuthDetails{Username: "admin", Password: &secret}},
+		EntryWebsite{EntryName: "site", WebsiteDetails: EntryWebsiteAuthDetails{Username: "admin", Password: &secret}},
+	}
+
+	for _, value := range values {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			assert.NotContains(t, fmt.Sprintf(format, value), secret, format)
+			assert.NotContains(t, fmt.Sprintf(format, &value), secret, format)
+		}
+
+		for _, handler := range []func(io.Writer) slog.Handler{
+			func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, nil) },
+			func(w io.Writer) slog.Handler { return slog.NewTextHandler(w, nil) },
+		} {
+			var buf bytes.Buffer
+			slog.New(handler(&buf)).Info("entry", "entry", value)
+			assert.NotContains(t, buf.String(), secret, buf.String())
+		}
+	}
+
+	data := EntryCredentialDefaultData{Username: "admin", Password: secret}
+	assert.Equal(t, "{Domain: Password:[REDACTED] Username:admin}", fmt.Sprintf("%+v", data))
+	assert.Equal(t, `dvls.EntryCredentialDefaultData{Domain:"", Password:"[REDACTED]", Username:"admin"}`, fmt.Sprintf("%#v", data))
+	assert.Equal(t, "{Domain: Password: Username:admin}", fmt.Sprintf("%+v", EntryCredentialDefaultData{Username: "admin"}))
+	assert.Contains(t, fmt.Sprintf("%+v", Entry{Data: &data}), "Password:[REDACTED] Username:admin")
+	assert.Equal(t, secret, data.Password)
+}
@@ -19,31 +19,44 @@ type Server struct {
 	SystemMessage string
 }
 
+// rawServer is the data field of the server information responses.
+type rawServer struct {
+	AccessUri          string
+	SelectedTimeZoneId string
+	ServerName         string
+	Version            string
+	SystemMessage      string
+}
+
+// serverResponse is the response of the server information endpoints.
+type serverResponse struct {
+	legacyResult
+	Data rawServer
+}
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (s *Server) UnmarshalJSON(d []byte) error {
-	raw := struct {
-		Data struct {
-			AccessUri          string
-			SelectedTimeZoneId string
-			ServerName         string
-			Version            string
-			SystemMessage      string
-		}
-	}{}
+	var raw serverResponse
 	err := json.Unmarshal(d, &raw)
 	if err != nil {
 		return err
 	}
 
-	s.TimeZone = raw.Data.SelectedTimeZoneId
-	s.AccessUri = raw.Data.AccessUri
-	s.ServerName = raw.Data.ServerName
-	s.Version = raw.Data.Version
-	s.SystemMessage = raw.Data.SystemMessage
+	*s = raw.Data.server()
 
 	return nil
 }
 
+func (r rawServer) server() Server {
+	return Server{
+		TimeZone:      r.SelectedTimeZoneId,
+		AccessUri:     r.AccessUri,
+		ServerName:    r.ServerName,
+		Version:       r.Version,
+		SystemMessage: r.SystemMessage,
+	}
+}
+
 // Timezone represents a Server timezone.
 type Timezone struct {
 	Id                         string
@@ -77,8 +90,18 @@ type TimezoneAdjustmentRuleTransitionTime struct {
 }
 
 // ServerTime represents a time.Time that parses the correct server time layout.
+// Times sent without an offset are parsed as UTC; use a ServerZone to interpret them in the server time zone.
 type ServerTime struct {
 	time.Time
+
+	// local is set when the time was sent without an offset.
+	local bool
+}
+
+// IsServerLocal reports whether the time was sent without an offset, in which case it is expressed in
+// the server time zone rather than in UTC.
+func (z ServerTime) IsServerLocal() bool {
+	return z.local
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -91,6 +114,7 @@ func (z *ServerTime) UnmarshalJSON(d []byte) error {
 	for _, layout := range serverTimeLayouts {
 		if dateParsed, err := time.Parse(layout, s); err == nil {
 			z.Time = dateParsed
+			z.local = !strings.Contains(layout, "Z")
 			return nil
 		}
 	}
@@ -117,27 +141,28 @@ func (c *Client) GetPublicServerInfo() (Server, error) {
 }
 
 // GetPublicServerInfoWithContext returns Server that contains public information on the DVLS instance.
+// The endpoint is public, so the request is sent without a session token.
 // The provided context can be used to cancel the request.
 func (c *Client) GetPublicServerInfoWithContext(ctx context.Context) (Server, error) {
-	var server Server
+	var respData legacyData
 	reqUrl, err := url.JoinPath(c.baseUri, serverPublicInfoEndpoint)
 	if err != nil {
 		return Server{}, fmt.Errorf("failed to build server info url: %w", err)
 	}
 
-	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	resp, err := c.sendRequestWithContext(ctx, "", reqUrl, http.MethodGet, defaultContentType, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return Server{}, fmt.Errorf("error while fetching server info: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return Server{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &server)
-	if err != nil {
-		return Server{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw rawServer
+	if err = respData.decode(&raw); err != nil {
+		return Server{}, err
 	}
 
-	return server, nil
+	return raw.server(), nil
 }
 
 // GetPrivateServerInfo returns Server that contains private information on the DVLS instance (need authentication).
@@ -148,25 +173,25 @@ func (c *Client) GetPrivateServerInfo() (Server, error) {
 // GetPrivateServerInfoWithContext returns Server that contains private information on the DVLS instance (need authentication).
 // The provided context can be used to cancel the request.
 func (c *Client) GetPrivateServerInfoWithContext(ctx context.Context) (Server, error) {
-	var server Server
+	var respData legacyData
 	reqUrl, err := url.JoinPath(c.baseUri, serverPrivateInfoEndpoint)
 	if err != nil {
 		return Server{}, fmt.Errorf("failed to build server info url: %w", err)
 	}
 
-	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return Server{}, fmt.Errorf("error while fetching server info: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return Server{}, err
 	}
 
-	err = json.Unmarshal(resp.Response, &server)
-	if err != nil {
-		return Server{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var raw rawServer
+	if err = respData.decode(&raw); err != nil {
+		return Server{}, err
 	}
 
-	return server, nil
+	return raw.server(), nil
 }
 
 // GetServerTimezones returns an array of Timezone that contains all of the available timezones on
@@ -179,28 +204,23 @@ func (c *Client) GetServerTimezones() ([]Timezone, error) {
 // the DVLS instance.
 // The provided context can be used to cancel the request.
 func (c *Client) GetServerTimezonesWithContext(ctx context.Context) ([]Timezone, error) {
-	var timezones []Timezone
+	var respData legacyData
 	reqUrl, err := url.JoinPath(c.baseUri, serverTimezonesEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build timezone info url: %w", err)
 	}
 
-	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	resp, err := c.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &respData})
 	if err != nil {
 		return nil, fmt.Errorf("error while fetching timezones: %w", err)
 	} else if err = resp.CheckRespSaveResult(); err != nil {
 		return nil, err
 	}
 
-	raw := struct {
-		Data []Timezone
-	}{}
-	err = json.Unmarshal(resp.Response, &raw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	var timezones []Timezone
+	if err = respData.decode(&timezones); err != nil {
+		return nil, err
 	}
 
-	timezones = raw.Data
-
 	return timezones, nil
 }
@@ -0,0 +1,294 @@
+package dvls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTimezoneNotFound is returned when the timezone of the server is not among the timezones of the
+// instance.
+var ErrTimezoneNotFound = errors.New("timezone not found")
+
+// ServerZone converts times using the rules of a DVLS Timezone, including its fixed and floating daylight
+// saving time transitions. DVLS timezones follow the Windows time zone model, which time.Location cannot
+// represent, hence this type.
+//
+// Wall clock times are given and returned as time.Time values whose location is ignored: only their date
+// and clock are used.
+type ServerZone struct {
+	timezone Timezone
+	base     time.Duration
+	rules    []zoneRule
+}
+
+// zoneRule is a parsed TimezoneAdjustmentRule.
+type zoneRule struct {
+	start         time.Time
+	end           time.Time
+	daylightDelta time.Duration
+	baseDelta     time.Duration
+	noTransitions bool
+
+	transitionStart TimezoneAdjustmentRuleTransitionTime
+	transitionEnd   TimezoneAdjustmentRuleTransitionTime
+}
+
+// NewServerZone returns the ServerZone described by timezone, as returned by GetServerTimezones.
+func NewServerZone(timezone Timezone) (*ServerZone, error) {
+	base, err := parseTimeSpan(timezone.BaseUtcOffset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base UTC offset of timezone %s: %w", timezone.Id, err)
+	}
+
+	zone := &ServerZone{timezone: timezone, base: base}
+
+	for i, rule := range timezone.AdjustmentRules {
+		daylightDelta, err := parseTimeSpan(rule.DaylightDelta)
+		if err != nil {
+			return nil, fmt.Errorf("invalid daylight delta of timezone %s (rule %d): %w", timezone.Id, i, err)
+		}
+		baseDelta, err := parseTimeSpan(rule.BaseUtcOffsetDelta)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base UTC offset delta of timezone %s (rule %d): %w", timezone.Id, i, err)
+		}
+
+		zone.rules = append(zone.rules, zoneRule{
+			start:           wallClock(rule.DateStart.Time),
+			end:             wallClock(rule.DateEnd.Time),
+			daylightDelta:   daylightDelta,
+			baseDelta:       baseDelta,
+			noTransitions:   rule.NoDaylightTransitions,
+			transitionStart: rule.DaylightTransitionStart,
+			transitionEnd:   rule.DaylightTransitionEnd,
+		})
+	}
+
+	return zone, nil
+}
+
+// Timezone returns the DVLS timezone the zone was built from.
+func (z *ServerZone) Timezone() Timezone {
+	return z.timezone
+}
+
+// Offset returns the UTC offset of the zone at the instant t.
+func (z *ServerZone) Offset(t time.Time) time.Duration {
+	offset, _ := z.lookup(t)
+	return offset
+}
+
+// IsDaylightTime reports whether daylight saving time is in effect in the zone at the instant t.
+func (z *ServerZone) IsDaylightTime(t time.Time) bool {
+	_, daylight := z.lookup(t)
+	return daylight
+}
+
+// Local returns the instant t in the zone, with a fixed location named after the standard or daylight
+// name of the timezone.
+func (z *ServerZone) Local(t time.Time) time.Time {
+	offset, daylight := z.lookup(t)
+
+	name := z.timezone.StandardName
+	if daylight {
+		name = z.timezone.DaylightName
+	}
+
+	return t.In(time.FixedZone(name, int(offset/time.Second)))
+}
+
+// FromLocal returns the instant, in UTC, at which the wall clock of the zone shows the date and clock of
+// wall. Wall clock times repeated or skipped by a daylight saving time transition are resolved as daylight time.
+func (z *ServerZone) FromLocal(wall time.Time) time.Time {
+	w := wallClock(wall)
+
+	offset := z.base
+	if rule := z.rule(w); rule != nil {
+		offset += rule.baseDelta
+		if rule.daylight(w, w) {
+			offset += rule.daylightDelta
+		}
+	}
+
+	return w.Add(-offset)
+}
+
+// Time returns the instant of a time sent by DVLS. Times sent without an offset, such as the CreatedOn and
+// ModifiedOn fields of an Entry, are expressed in the server time zone and interpreted with the zone.
+func (z *ServerZone) Time(t ServerTime) time.Time {
+	if !t.IsServerLocal() {
+		return t.Time
+	}
+
+	return z.FromLocal(t.Time)
+}
+
+// In returns the instant of a time sent by DVLS in loc. See Time.
+func (z *ServerZone) In(t ServerTime, loc *time.Location) time.Time {
+	return z.Time(t).In(loc)
+}
+
+// lookup returns the UTC offset at the instant t and whether daylight saving time is in effect.
+func (z *ServerZone) lookup(t time.Time) (time.Duration, bool) {
+	utc := wallClock(t.UTC())
+
+	rule := z.rule(utc.Add(z.base))
+	if rule == nil {
+		return z.base, false
+	}
+
+	offset := z.base + rule.baseDelta
+	standard := utc.Add(offset)
+	if rule.daylight(standard, standard.Add(rule.daylightDelta)) {
+		return offset + rule.daylightDelta, true
+	}
+
+	return offset, false
+}
+
+// rule returns the adjustment rule in effect at the wall clock time w, or nil when none is.
+func (z *ServerZone) rule(w time.Time) *zoneRule {
+	for i := range z.rules {
+		if z.rules[i].contains(w) {
+			return &z.rules[i]
+		}
+	}
+
+	return nil
+}
+
+// contains reports whether the rule is in effect at the wall clock time w. Rules with transitions apply
+// to whole days, from the date of DateStart to the date of DateEnd.
+func (r *zoneRule) contains(w time.Time) bool {
+	if r.noTransitions {
+		return !w.Before(r.start) && !w.After(r.end)
+	}
+
+	day := time.Date(w.Year(), w.Month(), w.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(r.start.Truncate(24*time.Hour)) && !day.After(r.end)
+}
+
+// daylight reports whether daylight saving time is in effect, given the wall clock time in standard and
+// daylight time. The start transition is expressed in standard time and the end transition in daylight time.
+func (r *zoneRule) daylight(standard time.Time, daylight time.Time) bool {
+	if r.daylightDelta == 0 {
+		return false
+	}
+	if r.noTransitions {
+		return true
+	}
+
+	start := transitionTime(standard.Year(), r.transitionStart)
+	end := transitionTime(standard.Year(), r.transitionEnd)
+	if start.Before(end) {
+		return !standard.Before(start) && daylight.Before(end)
+	}
+
+	// Southern hemisphere: daylight saving time spans the new year.
+	return !standard.Before(start) || daylight.Before(end)
+}
+
+// transitionTime returns the wall clock time of a transition in the given year. Floating transitions fall
+// on the nth day of the week of the month, week 5 standing for the last one.
+func transitionTime(year int, transition TimezoneAdjustmentRuleTransitionTime) time.Time {
+	hour, minute, second := transition.TimeOfDay.Clock()
+	nanosecond := transition.TimeOfDay.Nanosecond()
+	month := time.Month(transition.Month)
+	days := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	if transition.IsFixedDateRule {
+		return time.Date(year, month, min(transition.Day, days), hour, minute, second, nanosecond, time.UTC)
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	day := 1 + (transition.DayOfWeek-int(first)+7)%7 + (transition.Week-1)*7
+	for day > days {
+		day -= 7
+	}
+
+	return time.Date(year, month, day, hour, minute, second, nanosecond, time.UTC)
+}
+
+// wallClock returns the date and clock of t in the UTC location.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// parseTimeSpan parses a .NET TimeSpan such as "-05:00:00", "05:30:00" or "1.02:00:00". An empty string is zero.
+func parseTimeSpan(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	value, negative := strings.CutPrefix(s, "-")
+
+	var days time.Duration
+	if dot, colon := strings.IndexByte(value, '.'), strings.IndexByte(value, ':'); dot >= 0 && dot < colon {
+		n, err := strconv.Atoi(value[:dot])
+		if err != nil {
+			return 0, fmt.Errorf("invalid time span %q", s)
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		value = value[dot+1:]
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time span %q", s)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time span %q", s)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time span %q", s)
+	}
+
+	var seconds float64
+	if len(parts) == 3 {
+		seconds, err = strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time span %q", s)
+		}
+	}
+
+	d := days + time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second))
+	if negative {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// GetServerZone returns the ServerZone of the timezone selected on the DVLS instance.
+func (c *Client) GetServerZone() (*ServerZone, error) {
+	return c.GetServerZoneWithContext(context.Background())
+}
+
+// GetServerZoneWithContext returns the ServerZone of the timezone selected on the DVLS instance.
+// Returns ErrTimezoneNotFound if the timezone is not among the timezones of the instance.
+// The provided context can be used to cancel the request.
+func (c *Client) GetServerZoneWithContext(ctx context.Context) (*ServerZone, error) {
+	server, err := c.GetPublicServerInfoWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timezones, err := c.GetServerTimezonesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, timezone := range timezones {
+		if timezone.Id == server.TimeZone {
+			return NewServerZone(timezone)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTimezoneNotFound, server.TimeZone)
+}
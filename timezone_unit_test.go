@@ -0,0 +1,228 @@
+package dvls
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const easternTimezone = `{
+	"id": "Eastern Standard Time",
+	"standardName": "Eastern Standard Time",
+	"daylightName": "Eastern Daylight Time",
+	"baseUtcOffset": "-05:00:00",
+	"supportsDaylightSavingTime": true,
+	"adjustmentRules": [{
+		"dateStart": "2007-01-01T00:00:00",
+		"dateEnd": "9999-12-31T00:00:00",
+		"daylightDelta": "01:00:00",
+		"daylightTransitionStart": {"timeOfDay": "0001-01-01T02:00:00", "month": 3, "week": 2, "day": 1, "dayOfWeek": 0, "isFixedDateRule": false},
+		"daylightTransitionEnd": {"timeOfDay": "0001-01-01T02:00:00", "month": 11, "week": 1, "day": 1, "dayOfWeek": 0, "isFixedDateRule": false},
+		"baseUtcOffsetDelta": "00:00:00",
+		"noDaylightTransitions": false
+	}]
+}`
+
+const sydneyTimezone = `{
+	"id": "AUS Eastern Standard Time",
+	"standardName": "AUS Eastern Standard Time",
+	"daylightName": "AUS Eastern Daylight Time",
+	"baseUtcOffset": "10:00:00",
+	"supportsDaylightSavingTime": true,
+	"adjustmentRules": [{
+		"dateStart": "2008-01-01T00:00:00",
+		"dateEnd": "9999-12-31T00:00:00",
+		"daylightDelta": "01:00:00",
+		"daylightTransitionStart": {"timeOfDay": "0001-01-01T02:00:00", "month": 10, "week": 1, "day": 1, "dayOfWeek": 0, "isFixedDateRule": false},
+		"daylightTransitionEnd": {"timeOfDay": "0001-01-01T03:00:00", "month": 4, "week": 1, "day": 1, "dayOfWeek": 0, "isFixedDateRule": false},
+		"baseUtcOffsetDelta": "00:00:00",
+		"noDaylightTransitions": false
+	}]
+}`
+
+const fixedDateTimezone = `{
+	"id": "Fixed Date Time",
+	"standardName": "Fixed Standard Time",
+	"daylightName": "Fixed Daylight Time",
+	"baseUtcOffset": "03:30:00",
+	"supportsDaylightSavingTime": true,
+	"adjustmentRules": [{
+		"dateStart": "2010-01-01T00:00:00",
+		"dateEnd": "2030-12-31T00:00:00",
+		"daylightDelta": "01:00:00",
+		"daylightTransitionStart": {"timeOfDay": "0001-01-01T00:00:00", "month": 3, "week": 1, "day": 22, "dayOfWeek": 0, "isFixedDateRule": true},
+		"daylightTransitionEnd": {"timeOfDay": "0001-01-01T00:00:00", "month": 9, "week": 1, "day": 22, "dayOfWeek": 0, "isFixedDateRule": true},
+		"baseUtcOffsetDelta": "00:00:00",
+		"noDaylightTransitions": false
+	}]
+}`
+
+func newTestServerZone(t *testing.T, data string) *ServerZone {
+	t.Helper()
+
+	var timezone Timezone
+	require.NoError(t, json.Unmarshal([]byte(data), &timezone))
+
+	zone, err := NewServerZone(timezone)
+	require.NoError(t, err)
+
+	return zone
+}
+
+func TestServerZone_FloatingTransitions(t *testing.T) {
+	zone := newTestServerZone(t, easternTimezone)
+
+	tests := []struct {
+		instant string
+		offset  time.Duration
+	}{
+		{instant: "2026-01-15T12:00:00Z", offset: -5 * time.Hour},
+		{instant: "2026-03-08T06:59:59Z", offset: -5 * time.Hour},
+		{instant: "2026-03-08T07:00:00Z", offset: -4 * time.Hour},
+		{instant: "2026-11-01T05:59:59Z", offset: -4 * time.Hour},
+		{instant: "2026-11-01T06:00:00Z", offset: -5 * time.Hour},
+		{instant: "2006-07-01T12:00:00Z", offset: -5 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		instant, err := time.Parse(time.RFC3339, tt.instant)
+		require.NoError(t, err)
+		assert.Equal(t, tt.offset, zone.Offset(instant), tt.instant)
+	}
+
+	local := zone.Local(time.Date(2026, 7, 1, 14, 0, 0, 0, time.UTC))
+	assert.Equal(t, 10, local.Hour())
+	name, _ := local.Zone()
+	assert.Equal(t, "Eastern Daylight Time", name)
+}
+
+func TestServerZone_SouthernHemisphere(t *testing.T) {
+	zone := newTestServerZone(t, sydneyTimezone)
+
+	// Daylight saving time ends on 2026-04-05 at 03:00 and starts on 2026-10-04 at 02:00.
+	assert.True(t, zone.IsDaylightTime(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, zone.IsDaylightTime(time.Date(2026, 4, 4, 15, 59, 59, 0, time.UTC)))
+	assert.False(t, zone.IsDaylightTime(time.Date(2026, 4, 4, 16, 0, 0, 0, time.UTC)))
+	assert.False(t, zone.IsDaylightTime(time.Date(2026, 10, 3, 15, 59, 59, 0, time.UTC)))
+	assert.True(t, zone.IsDaylightTime(time.Date(2026, 10, 3, 16, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 11*time.Hour, zone.Offset(time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)))
+}
+
+func TestServerZone_FixedTransitions(t *testing.T) {
+	zone := newTestServerZone(t, fixedDateTimezone)
+
+	// Daylight saving time starts on March 22 at 00:00 standard time, 20:30 UTC the day before.
+	assert.Equal(t, 3*time.Hour+30*time.Minute, zone.Offset(time.Date(2026, 3, 21, 20, 29, 59, 0, time.UTC)))
+	assert.Equal(t, 4*time.Hour+30*time.Minute, zone.Offset(time.Date(2026, 3, 21, 20, 30, 0, 0, time.UTC)))
+	assert.Equal(t, 4*time.Hour+30*time.Minute, zone.Offset(time.Date(2026, 9, 21, 19, 29, 59, 0, time.UTC)))
+	assert.Equal(t, 3*time.Hour+30*time.Minute, zone.Offset(time.Date(2026, 9, 21, 19, 30, 0, 0, time.UTC)))
+	assert.Equal(t, 3*time.Hour+30*time.Minute, zone.Offset(time.Date(2031, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestServerZone_MatchesTZDatabase(t *testing.T) {
+	tests := []struct {
+		timezone string
+		location string
+	}{
+		{timezone: easternTimezone, location: "America/New_York"},
+		{timezone: sydneyTimezone, location: "Australia/Sydney"},
+	}
+
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.location)
+		if err != nil {
+			t.Skipf("time zone database unavailable: %v", err)
+		}
+		zone := newTestServerZone(t, tt.timezone)
+
+		for instant := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); instant.Year() < 2027; instant = instant.Add(15 * time.Minute) {
+			_, offset := instant.In(loc).Zone()
+			require.Equal(t, time.Duration(offset)*time.Second, zone.Offset(instant), "%s at %s", tt.location, instant)
+		}
+	}
+}
+
+func TestServerZone_FromLocal(t *testing.T) {
+	zone := newTestServerZone(t, easternTimezone)
+
+	tests := []struct {
+		name string
+		wall time.Time
+		want time.Time
+	}{
+		{name: "standard", wall: time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), want: time.Date(2026, 1, 15, 15, 0, 0, 0, time.UTC)},
+		{name: "daylight", wall: time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC), want: time.Date(2026, 7, 1, 14, 0, 0, 0, time.UTC)},
+		{name: "skipped", wall: time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC), want: time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC)},
+		{name: "repeated", wall: time.Date(2026, 11, 1, 1, 30, 0, 0, time.UTC), want: time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, zone.FromLocal(tt.wall))
+		})
+	}
+}
+
+func TestServerZone_ServerTime(t *testing.T) {
+	zone := newTestServerZone(t, easternTimezone)
+
+	var times struct {
+		CreatedOn  ServerTime `json:"createdOn"`
+		ModifiedOn ServerTime `json:"modifiedOn"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"createdOn":"2026-07-01T10:00:00","modifiedOn":"2026-07-01T10:00:00Z"}`), &times))
+	assert.True(t, times.CreatedOn.IsServerLocal())
+	assert.False(t, times.ModifiedOn.IsServerLocal())
+
+	paris := time.FixedZone("CEST", 2*60*60)
+	assert.Equal(t, time.Date(2026, 7, 1, 16, 0, 0, 0, paris), zone.In(times.CreatedOn, paris))
+	assert.Equal(t, time.Date(2026, 7, 1, 12, 0, 0, 0, paris), zone.In(times.ModifiedOn, paris))
+}
+
+func TestParseTimeSpan(t *testing.T) {
+	tests := []struct {
+		span    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{span: "", want: 0},
+		{span: "-05:00:00", want: -5 * time.Hour},
+		{span: "05:30:00", want: 5*time.Hour + 30*time.Minute},
+		{span: "1.02:00:00", want: 26 * time.Hour},
+		{span: "00:00:30.5", want: 30*time.Second + 500*time.Millisecond},
+		{span: "five", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.span, func(t *testing.T) {
+			got, err := parseTimeSpan(tt.span)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetServerZone(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/public-instance-information", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":1,"data":{"selectedTimeZoneId":"Eastern Standard Time"}}`))
+	})
+	mux.HandleFunc("/api/configuration/timezones", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":1,"data":[` + sydneyTimezone + `,` + easternTimezone + `]}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	zone, err := client.GetServerZoneWithContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Eastern Standard Time", zone.Timezone().Id)
+}
@@ -0,0 +1,187 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenManager tracks the session token used to authenticate requests and its age.
+// It is safe for concurrent use.
+type tokenManager struct {
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+	maxAge   time.Duration
+
+	// renewal is the login in progress, if any. Requests that need a new token while a
+	// login is running wait for it instead of starting their own.
+	renewal *tokenRenewal
+
+	// closed is set once the client has logged out. No token is stored or issued afterwards.
+	closed bool
+}
+
+// tokenRenewal is a login shared by every request waiting for a new token.
+type tokenRenewal struct {
+	done chan struct{}
+	err  error
+
+	// canceled reports whether the login failed because the context of the request running it ended.
+	canceled bool
+}
+
+// set stores a freshly issued token. Tokens issued after the client was closed are discarded.
+func (t *tokenManager) set(token string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.token = token
+	t.issuedAt = now
+}
+
+// current returns the token to send with requests.
+func (t *tokenManager) current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.token
+}
+
+// isClosed reports whether the client has logged out.
+func (t *tokenManager) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.closed
+}
+
+// usable returns the current token and reports whether it is available and young enough to be used
+// without renewing it. A zero maxAge disables proactive renewal.
+func (t *tokenManager) usable(now time.Time) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		return "", false
+	}
+
+	return t.token, t.maxAge <= 0 || now.Sub(t.issuedAt) < t.maxAge
+}
+
+// WithTokenMaxAge sets the age after which the session token is renewed before sending a request.
+// Proactive renewal is disabled by default, or with a zero value; expired tokens are still renewed when
+// the server rejects them. The replaced token is not logged out, so its session stays open on the server
+// until it expires.
+func WithTokenMaxAge(maxAge time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.tokenMaxAge = &maxAge
+	}
+}
+
+// isAuthenticationExpired reports whether a request was rejected because the session token is no longer valid.
+func isAuthenticationExpired(resp Response, err error) bool {
+	if err != nil {
+		var reqErr *RequestError
+		return errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusUnauthorized
+	}
+
+	return SaveResult(resp.Result) == SaveResultWebApiRedirectToLogin
+}
+
+// readRequestBody buffers the request body so it can be replayed.
+func readRequestBody(reqBody io.Reader) ([]byte, error) {
+	if reqBody == nil {
+		return nil, nil
+	}
+
+	return io.ReadAll(reqBody)
+}
+
+// newBodyReader returns a reader over a buffered request body, or nil when there is no body.
+func newBodyReader(body []byte) io.Reader {
+	if body == nil {
+		return nil
+	}
+
+	return bytes.NewReader(body)
+}
+
+// ensureToken returns a token that can be used for a request, logging in when no token is available
+// or when the current token is due for renewal.
+func (c *Client) ensureToken(ctx context.Context) (string, error) {
+	if c.tokens.isClosed() {
+		return "", ErrClientClosed
+	}
+
+	token, ok := c.tokens.usable(time.Now())
+	if ok {
+		return token, nil
+	}
+
+	err := c.renewToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	return c.tokens.current(), nil
+}
+
+// renewToken replaces the stale token with a new one. Concurrent callers share a single login, and
+// callers whose stale token was already replaced by another request return without logging in. The
+// shared login runs with the context of the request that started it; when that context ends the
+// login, waiters whose own context is still live start a new one instead of failing with it.
+func (c *Client) renewToken(ctx context.Context, stale string) error {
+	t := &c.tokens
+
+	for {
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			return ErrClientClosed
+		}
+
+		if t.token != stale {
+			t.mu.Unlock()
+			return nil
+		}
+
+		renewal := t.renewal
+		if renewal == nil {
+			break
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-renewal.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if !renewal.canceled || ctx.Err() != nil {
+			return renewal.err
+		}
+	}
+
+	renewal := &tokenRenewal{done: make(chan struct{})}
+	t.renewal = renewal
+	t.mu.Unlock()
+
+	renewal.err = c.loginWithContext(ctx)
+	renewal.canceled = renewal.err != nil && ctx.Err() != nil
+
+	t.mu.Lock()
+	t.renewal = nil
+	t.mu.Unlock()
+	close(renewal.done)
+
+	return renewal.err
+}
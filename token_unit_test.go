@@ -0,0 +1,307 @@
+package dvls
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_DoesNotCheckLoginStatus(t *testing.T) {
+	var isLoggedCount int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/is-logged", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&isLoggedCount, 1)
+		w.Write([]byte("true"))
+	})
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri: server.URL,
+		client:  server.Client(),
+		tokens:  tokenManager{token: "test-token", issuedAt: time.Now(), maxAge: time.Hour},
+	}
+
+	for range 3 {
+		_, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&isLoggedCount))
+}
+
+func TestRequest_RenewsExpiredToken(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	var tokenSent string
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		tokenSent = r.Header.Get("tokenId")
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri:       server.URL,
+		client:        server.Client(),
+		authenticator: AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"},
+		tokens:        tokenManager{token: "old-token", issuedAt: time.Now().Add(-time.Hour), maxAge: time.Minute},
+	}
+
+	_, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+	assert.Equal(t, "mock-token-123", tokenSent)
+}
+
+func TestNewClient_NoProactiveRenewalByDefault(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+	client.tokens.set("mock-token-123", time.Now().Add(-24*time.Hour))
+
+	_, err = client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+}
+
+func TestRequest_ReplaysAfterUnauthorized(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	var bodies []string
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("tokenId") != "mock-token-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"created"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri:       server.URL,
+		client:        server.Client(),
+		authenticator: AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"},
+		tokens:        tokenManager{token: "revoked-token"},
+	}
+
+	resp, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault", http.MethodPost, bytes.NewBufferString(`{"name":"v"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"created"}`, string(resp.Response))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+	assert.Equal(t, []string{`{"name":"v"}`, `{"name":"v"}`}, bodies)
+}
+
+func TestRequest_ReplaysAfterRedirectToLogin(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	mux.HandleFunc("/api/connections/partial/test", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("tokenId") != "mock-token-123" {
+			w.Write([]byte(`{"result":10}`))
+			return
+		}
+		w.Write([]byte(`{"result":1,"data":{"id":"test"}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri:       server.URL,
+		client:        server.Client(),
+		authenticator: AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"},
+		tokens:        tokenManager{token: "revoked-token"},
+	}
+
+	resp, err := client.RequestWithContext(t.Context(), server.URL+"/api/connections/partial/test", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SaveResultSuccess, SaveResult(resp.Result))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+}
+
+func TestRequest_ReplaysOnlyOnce(t *testing.T) {
+	var loginCount int32
+	var requestCount int32
+	mux := newLoginMux(t, &loginCount)
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri:       server.URL,
+		client:        server.Client(),
+		authenticator: AppKeyAuthenticator{AppKey: "test-key", AppSecret: "test-secret"},
+		tokens:        tokenManager{token: "test-token"},
+	}
+
+	_, err := client.RequestWithContext(t.Context(), server.URL+"/api/v1/vault/test", http.MethodGet, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*RequestError).StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount))
+}
+
+func TestIsLogged_UnexpectedResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/is-logged", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":1}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &Client{
+		baseUri: server.URL,
+		client:  server.Client(),
+		tokens:  tokenManager{token: "test-token"},
+	}
+
+	_, err := client.isLogged()
+	assert.Error(t, err)
+}
+
+func TestRequest_ConcurrentUnauthorizedLogsInOnce(t *testing.T) {
+	var loginCount int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&loginCount, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"result":1,"tokenId":"new-token"}`))
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v1/vault/%s/entry/", testVaultID), func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("tokenId") != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"entry","name":"Entry","type":"Credential","subType":"Default","data":{"username":"u"}}`))
+	})
+
+	client := newTestClient(t, mux)
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := range workers {
+		wg.Go(func() {
+			_, err := client.Entries.Credential.GetByIdWithContext(t.Context(), testVaultID, fmt.Sprintf("entry-%d", i))
+			errs <- err
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+	assert.Equal(t, "new-token", client.tokens.current())
+}
+
+func TestRequest_ConcurrentExpiredTokenLogsInOnce(t *testing.T) {
+	var loginCount int32
+	mux := newLoginMux(t, &loginCount)
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			_, err := client.Vaults.GetWithContext(t.Context(), "test")
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loginCount))
+}
+
+func TestRequest_WaiterRetriesLoginCanceledByLeader(t *testing.T) {
+	var loginCount int32
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&loginCount, 1) == 1 {
+			close(started)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte(`{"result":1,"tokenId":"new-token"}`))
+	})
+	mux.HandleFunc("/api/v1/vault/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"test"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL, WithLazyLogin())
+	require.NoError(t, err)
+
+	leaderCtx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var leaderErr error
+	wg.Go(func() {
+		_, leaderErr = client.Vaults.GetWithContext(leaderCtx, "test")
+	})
+
+	<-started
+	_, err = client.Vaults.GetWithContext(t.Context(), "test")
+	wg.Wait()
+
+	require.NoError(t, err)
+	assert.ErrorIs(t, leaderErr, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&loginCount))
+	assert.Equal(t, "new-token", client.tokens.current())
+}
+
+func TestNewClient_ServicesShareClient(t *testing.T) {
+	server := httptest.NewServer(newLoginMux(t, nil))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret", server.URL)
+	require.NoError(t, err)
+	assert.Same(t, client, client.Vaults.client)
+	assert.Same(t, client, client.Entries.Credential.client)
+}
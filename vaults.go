@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 )
@@ -78,47 +79,92 @@ func (c *Vaults) List() ([]Vault, error) {
 }
 
 // ListWithContext returns all vaults.
-// This function handles pagination automatically and returns all vaults across all pages.
+// This function handles pagination automatically and returns all vaults across all pages. After the
+// first page, the remaining pages are fetched concurrently, see WithPageConcurrency.
 // The provided context can be used to cancel the request.
 func (c *Vaults) ListWithContext(ctx context.Context) ([]Vault, error) {
-	reqUrl, err := url.JoinPath(c.client.baseUri, vaultEndpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build vault url: %w", err)
+	return fetchAllPages(ctx, c.client.pageConcurrency, func(ctx context.Context, page int) ([]Vault, int, error) {
+		listResp, err := c.listPage(ctx, PageOptions{Page: page})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return listResp.Data, listResp.TotalPage, nil
+	})
+}
+
+// All returns an iterator over all vaults. Pages are fetched lazily as the iteration progresses, and no
+// further page is fetched once the caller stops iterating. The iteration stops after the first error.
+// The provided context can be used to cancel the requests.
+func (c *Vaults) All(ctx context.Context) iter.Seq2[Vault, error] {
+	return func(yield func(Vault, error) bool) {
+		for currentPage := 1; ; currentPage++ {
+			listResp, err := c.listPage(ctx, PageOptions{Page: currentPage})
+			if err != nil {
+				yield(Vault{}, err)
+				return
+			}
+
+			for _, vault := range listResp.Data {
+				if !yield(vault, nil) {
+					return
+				}
+			}
+
+			// Check if we've fetched all pages
+			if currentPage >= listResp.TotalPage {
+				return
+			}
+		}
 	}
+}
 
-	parsedUrl, err := url.Parse(reqUrl)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse vault url: %w", err)
+// ListPage returns a single page of vaults along with the pagination totals, so that callers can
+// show progress, resume from a given page or count the vaults without fetching them all.
+// The provided context can be used to cancel the request.
+func (c *Vaults) ListPage(ctx context.Context, opts PageOptions) (Page[Vault], error) {
+	if err := opts.validate(); err != nil {
+		return Page[Vault]{}, err
 	}
 
-	var allVaults []Vault
-	currentPage := 1
+	listResp, err := c.listPage(ctx, opts)
+	if err != nil {
+		return Page[Vault]{}, err
+	}
 
-	for {
-		q := parsedUrl.Query()
-		q.Set("page", fmt.Sprintf("%d", currentPage))
-		parsedUrl.RawQuery = q.Encode()
+	return Page[Vault]{
+		Items:       listResp.Data,
+		CurrentPage: listResp.CurrentPage,
+		PageSize:    listResp.PageSize,
+		TotalCount:  listResp.TotalCount,
+		TotalPage:   listResp.TotalPage,
+	}, nil
+}
 
-		resp, err := c.client.RequestWithContext(ctx, parsedUrl.String(), http.MethodGet, nil)
-		if err != nil {
-			return nil, fmt.Errorf("error while fetching vaults (page %d): %w", currentPage, err)
-		}
+// listPage fetches a single page of vaults.
+func (c *Vaults) listPage(ctx context.Context, opts PageOptions) (vaultListResponse, error) {
+	currentPage := opts.page()
+	reqUrl, err := url.JoinPath(c.client.baseUri, vaultEndpoint)
+	if err != nil {
+		return vaultListResponse{}, fmt.Errorf("failed to build vault url: %w", err)
+	}
 
-		var listResp vaultListResponse
-		if err := json.Unmarshal(resp.Response, &listResp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response body (page %d): %w", currentPage, err)
-		}
+	parsedUrl, err := url.Parse(reqUrl)
+	if err != nil {
+		return vaultListResponse{}, fmt.Errorf("failed to parse vault url: %w", err)
+	}
 
-		allVaults = append(allVaults, listResp.Data...)
+	q := parsedUrl.Query()
+	opts.setQuery(q)
+	parsedUrl.RawQuery = q.Encode()
 
-		// Check if we've fetched all pages
-		if currentPage >= listResp.TotalPage {
-			break
-		}
-		currentPage++
+	var listResp vaultListResponse
+	_, err = c.client.RequestWithContext(ctx, parsedUrl.String(), http.MethodGet, nil, RequestOptions{into: &listResp})
+	if err != nil {
+		return vaultListResponse{}, fmt.Errorf("error while fetching vaults (page %d): %w", currentPage, err)
 	}
 
-	return allVaults, nil
+	return listResp, nil
 }
 
 // Get returns a single Vault based on vaultId.
@@ -135,16 +181,11 @@ func (c *Vaults) GetWithContext(ctx context.Context, vaultId string) (Vault, err
 		return Vault{}, fmt.Errorf("failed to build vault url: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodGet, nil, RequestOptions{into: &vault})
 	if err != nil {
 		return Vault{}, fmt.Errorf("error while fetching vault: %w", err)
 	}
 
-	err = json.Unmarshal(resp.Response, &vault)
-	if err != nil {
-		return Vault{}, fmt.Errorf("failed to unmarshal response body: %w", err)
-	}
-
 	return vault, nil
 }
 
@@ -215,15 +256,10 @@ func (c *Vaults) NewWithContext(ctx context.Context, vault Vault) (Vault, error)
 		return Vault{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(vaultJson))
-	if err != nil {
-		return Vault{}, fmt.Errorf("error while creating vault: %w", err)
-	}
-
 	var createdVault Vault
-	err = json.Unmarshal(resp.Response, &createdVault)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodPost, bytes.NewBuffer(vaultJson), RequestOptions{into: &createdVault})
 	if err != nil {
-		return Vault{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+		return Vault{}, fmt.Errorf("error while creating vault: %w", err)
 	}
 
 	return createdVault, nil
@@ -255,15 +291,10 @@ func (c *Vaults) UpdateWithContext(ctx context.Context, vault Vault) (Vault, err
 		return Vault{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	resp, err := c.client.RequestWithContext(ctx, reqUrl, http.MethodPut, bytes.NewBuffer(vaultJson))
-	if err != nil {
-		return Vault{}, fmt.Errorf("error while updating vault: %w", err)
-	}
-
 	var updatedVault Vault
-	err = json.Unmarshal(resp.Response, &updatedVault)
+	_, err = c.client.RequestWithContext(ctx, reqUrl, http.MethodPut, bytes.NewBuffer(vaultJson), RequestOptions{into: &updatedVault})
 	if err != nil {
-		return Vault{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+		return Vault{}, fmt.Errorf("error while updating vault: %w", err)
 	}
 
 	return updatedVault, nil
@@ -1,6 +1,7 @@
 package dvls
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -45,6 +46,95 @@ func TestVaultsList_Pagination(t *testing.T) {
 	assert.Equal(t, "vault-2", vaults[1].Id)
 }
 
+func TestVaultsAll_StopsFetching(t *testing.T) {
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(vaultListResponse{
+			Data:        []Vault{{Id: "vault-" + page + "a"}, {Id: "vault-" + page + "b"}},
+			CurrentPage: 1,
+			PageSize:    2,
+			TotalCount:  6,
+			TotalPage:   3,
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	var ids []string
+	for vault, err := range client.Vaults.All(context.Background()) {
+		require.NoError(t, err)
+		ids = append(ids, vault.Id)
+		if vault.Id == "vault-2a" {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"vault-1a", "vault-1b", "vault-2a"}, ids)
+	assert.Equal(t, []string{"1", "2"}, pages)
+}
+
+func TestVaultsAll_Error(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(vaultListResponse{Data: []Vault{{Id: "vault-1"}}, TotalPage: 2})
+	})
+
+	client := newTestClient(t, mux)
+
+	var ids []string
+	var errs []error
+	for vault, err := range client.Vaults.All(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, vault.Id)
+	}
+
+	assert.Equal(t, []string{"vault-1"}, ids)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "page 2")
+}
+
+func TestVaultsListPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "3", r.URL.Query().Get("page"))
+		assert.Equal(t, "10", r.URL.Query().Get("pageSize"))
+		json.NewEncoder(w).Encode(vaultListResponse{
+			Data:        []Vault{{Id: "vault-21"}, {Id: "vault-22"}},
+			CurrentPage: 3,
+			PageSize:    10,
+			TotalCount:  22,
+			TotalPage:   3,
+		})
+	})
+
+	client := newTestClient(t, mux)
+
+	page, err := client.Vaults.ListPage(context.Background(), PageOptions{Page: 3, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, Page[Vault]{
+		Items:       []Vault{{Id: "vault-21"}, {Id: "vault-22"}},
+		CurrentPage: 3,
+		PageSize:    10,
+		TotalCount:  22,
+		TotalPage:   3,
+	}, page)
+	assert.False(t, page.HasNext())
+
+	_, err = client.Vaults.ListPage(context.Background(), PageOptions{Page: -1})
+	assert.EqualError(t, err, "invalid page -1")
+}
+
 func TestVaultsGetByName(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/vault", func(w http.ResponseWriter, r *http.Request) {